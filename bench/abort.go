@@ -0,0 +1,92 @@
+package bench
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// WatchEarlyAbort stops the run if the landing rate of the first early_abort_window transactions
+// is below early_abort_threshold, once the last of them had EarlyAbortGracePeriod to land
+func (b *Benchmark) WatchEarlyAbort() {
+	// the records were held for the check
+	defer b.releaseRecords()
+
+	ticker := time.NewTicker(EarlyAbortCheckInterval)
+	defer ticker.Stop()
+
+	window := int(GlobalConfig.EarlyAbortWindow)
+
+	// wait for the window to be sent, the check is skipped if the run ends first
+	for {
+		select {
+		case <-b.stopped:
+			return
+		case <-ticker.C:
+		}
+
+		b.mu.RLock()
+		sent := len(b.TxRecords)
+		b.mu.RUnlock()
+
+		if sent >= window {
+			break
+		}
+	}
+
+	select {
+	case <-b.stopped:
+		return
+	case <-time.After(EarlyAbortGracePeriod):
+	}
+
+	rate := b.EarlyLandingRate(window)
+	if rate >= GlobalConfig.EarlyAbortThreshold {
+		log.Info("Early landing rate above the abort threshold, continuing", "txs", window, "rate", fmt.Sprintf("%.1f%%", rate*100))
+		return
+	}
+
+	log.Warn(
+		"Early landing rate below the abort threshold, aborting the run",
+		"txs", window,
+		"rate", fmt.Sprintf("%.1f%%", rate*100),
+		"threshold", fmt.Sprintf("%.1f%%", GlobalConfig.EarlyAbortThreshold*100),
+	)
+
+	b.mu.Lock()
+	b.AbortedEarly = true
+	b.mu.Unlock()
+
+	b.Stop()
+}
+
+// EarlyLandingRate returns the landing rate of the first sent transactions
+func (b *Benchmark) EarlyLandingRate(count int) float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	records := []*TxRecord{}
+	for _, record := range b.TxRecords {
+		records = append(records, record)
+	}
+
+	slices.SortFunc(records, func(a, c *TxRecord) int {
+		return a.SendTime.Compare(c.SendTime)
+	})
+
+	records = records[:min(count, len(records))]
+	if len(records) == 0 {
+		return 0
+	}
+
+	landed := 0
+	for _, record := range records {
+		if record.Landed {
+			landed++
+		}
+	}
+
+	return float64(landed) / float64(len(records))
+}
@@ -0,0 +1,130 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/montanaflynn/stats"
+)
+
+// Aggregate holds the results of the repeated runs against a single endpoint
+type Aggregate struct {
+	Label string
+	Runs  int
+
+	// one value per run, the landing times are only collected for the runs where a transaction landed
+	LandingRates []float64
+	Medians      []float64
+	P90s         []float64
+}
+
+// Spread returns the mean and the standard deviation of the values
+func Spread(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	mean, _ := stats.Mean(values)
+	stdDev, _ := stats.StandardDeviation(values)
+
+	return mean, stdDev
+}
+
+// BuildAggregates groups the runs by endpoint, in the order of the endpoints
+func BuildAggregates(benchmarks []*Benchmark) []*Aggregate {
+	out := []*Aggregate{}
+	byLabel := make(map[string]*Aggregate)
+
+	for _, b := range benchmarks {
+		label := b.Endpoint.GetLabel()
+
+		aggregate, ok := byLabel[label]
+		if !ok {
+			aggregate = &Aggregate{Label: label}
+			byLabel[label] = aggregate
+			out = append(out, aggregate)
+		}
+
+		aggregate.Runs += 1
+
+		b.mu.RLock()
+		if b.SentTransactions > 0 {
+			aggregate.LandingRates = append(aggregate.LandingRates, float64(b.ProcessedTransactions)/float64(b.SentTransactions))
+		}
+
+		if landing, ok := b.LandingTimeStats(); ok {
+			aggregate.Medians = append(aggregate.Medians, durationToMs(landing.Median))
+			aggregate.P90s = append(aggregate.P90s, durationToMs(landing.P90))
+		}
+		b.mu.RUnlock()
+	}
+
+	return out
+}
+
+// MeetsMinLandingRate reports whether the landing rate of each endpoint, across its runs,
+// is at least min_landing_rate, the failing endpoints are logged
+func MeetsMinLandingRate(benchmarks []*Benchmark) bool {
+	if GlobalConfig.MinLandingRate <= 0 || GlobalConfig.DryRun || GlobalConfig.SplitMode != "" {
+		return true
+	}
+
+	labels := []string{}
+	sent := make(map[string]uint64)
+	landed := make(map[string]uint64)
+
+	for _, b := range benchmarks {
+		label := b.Endpoint.GetLabel()
+		if _, ok := sent[label]; !ok {
+			labels = append(labels, label)
+		}
+
+		b.mu.RLock()
+		sent[label] += b.SentTransactions
+		landed[label] += b.ProcessedTransactions
+		b.mu.RUnlock()
+	}
+
+	ok := true
+	for _, label := range labels {
+		// an endpoint that didn't accept any transaction fails the check
+		rate := 0.0
+		if sent[label] > 0 {
+			rate = float64(landed[label]) / float64(sent[label])
+		}
+
+		if rate < GlobalConfig.MinLandingRate {
+			log.Error("Landing rate below min_landing_rate", "endpoint", label, "rate", fmt.Sprintf("%.1f%%", rate*100), "min", fmt.Sprintf("%.1f%%", GlobalConfig.MinLandingRate*100))
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// msToDuration converts milliseconds to a duration, truncated to the millisecond
+func msToDuration(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond)).Truncate(time.Millisecond)
+}
+
+// DisplayAggregates logs the mean and the standard deviation across the runs of each endpoint
+func DisplayAggregates(aggregates []*Aggregate) {
+	for _, aggregate := range aggregates {
+		rate, rateStdDev := Spread(aggregate.LandingRates)
+		median, medianStdDev := Spread(aggregate.Medians)
+		p90, p90StdDev := Spread(aggregate.P90s)
+
+		SimpleLogger.Printf("")
+		SimpleLogger.Printf("Aggregate Results      : %d runs", aggregate.Runs)
+		if len(GlobalConfig.GetEndpoints()) > 1 {
+			SimpleLogger.Printf("Endpoint               : %s", aggregate.Label)
+		}
+		SimpleLogger.Printf("Mean Landing Rate      : %.1f%% (± %.1f%%)", rate*100, rateStdDev*100)
+
+		if len(aggregate.Medians) > 0 {
+			SimpleLogger.Printf("Mean Median Landing    : %s (± %s)", msToDuration(median), msToDuration(medianStdDev))
+			SimpleLogger.Printf("Mean P90 Landing       : %s (± %s)", msToDuration(p90), msToDuration(p90StdDev))
+		}
+	}
+}
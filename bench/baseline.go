@@ -0,0 +1,115 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+)
+
+// LoadBaseline reads a results file saved by a previous test
+func LoadBaseline(path string) *Results {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("error opening baseline file: %v", err)
+	}
+
+	var out Results
+	if err := json.Unmarshal(data, &out); err != nil {
+		log.Fatalf("error parsing baseline file: %v", err)
+	}
+
+	if len(out.Endpoints) == 0 {
+		log.Fatalf("baseline file %s contains no endpoint results", path)
+	}
+
+	return &out
+}
+
+// FindBaselineEndpoint returns the baseline results to compare the endpoint results with,
+// matching the label and the run first, then the label only, then the position in the test,
+// so that a single endpoint can be compared with a previous setup of a different url
+func FindBaselineEndpoint(baseline *Results, current EndpointResults, index int) *EndpointResults {
+	for i, candidate := range baseline.Endpoints {
+		if candidate.Label == current.Label && candidate.Run == current.Run {
+			return &baseline.Endpoints[i]
+		}
+	}
+
+	for i, candidate := range baseline.Endpoints {
+		if candidate.Label == current.Label {
+			return &baseline.Endpoints[i]
+		}
+	}
+
+	if index < len(baseline.Endpoints) {
+		return &baseline.Endpoints[index]
+	}
+
+	return nil
+}
+
+// DisplayBaselineComparison logs the difference of the landing rate
+// and the landing time percentiles of each endpoint with the baseline
+func DisplayBaselineComparison(baseline *Results, results *Results) {
+	SimpleLogger.Printf("")
+	SimpleLogger.Printf("Baseline Comparison    : %s (Test ID %s)", BaselineFile, baseline.TestID)
+
+	for i, current := range results.Endpoints {
+		previous := FindBaselineEndpoint(baseline, current, i)
+
+		SimpleLogger.Printf("")
+		if previous == nil {
+			SimpleLogger.Printf("Endpoint               : %s (no baseline)", current.Label)
+			continue
+		}
+
+		if previous.Label == current.Label {
+			SimpleLogger.Printf("Endpoint               : %s", current.Label)
+		} else {
+			SimpleLogger.Printf("Endpoint               : %s (baseline: %s)", current.Label, previous.Label)
+		}
+
+		SimpleLogger.Printf("Landing Rate           : %.1f%% vs %.1f%% (%s)",
+			current.LandingRate*100,
+			previous.LandingRate*100,
+			FormatChange(current.LandingRate*100, previous.LandingRate*100, "pts"),
+		)
+
+		if current.LandingTimes == nil || previous.LandingTimes == nil {
+			SimpleLogger.Printf("Landing Times          : not available in both results")
+			continue
+		}
+
+		for _, metric := range []struct {
+			name              string
+			current, previous float64
+		}{
+			{"Min", current.LandingTimes.Min, previous.LandingTimes.Min},
+			{"Median", current.LandingTimes.Median, previous.LandingTimes.Median},
+			{"P90", current.LandingTimes.P90, previous.LandingTimes.P90},
+			{"P95", current.LandingTimes.P95, previous.LandingTimes.P95},
+			{"P99", current.LandingTimes.P99, previous.LandingTimes.P99},
+			{"Max", current.LandingTimes.Max, previous.LandingTimes.Max},
+		} {
+			SimpleLogger.Printf("%-22s : %.0fms vs %.0fms (%s)", metric.name+" Landing", metric.current, metric.previous, FormatChange(metric.current, metric.previous, "ms"))
+		}
+	}
+}
+
+// FormatChange formats the absolute and relative change from the previous value, e.g. "+12ms, +3.4%"
+func FormatChange(current float64, previous float64, unit string) string {
+	diff := current - previous
+	format := "%+.0f%s"
+	if unit == "pts" {
+		format = "%+.1f %s"
+	}
+	change := fmt.Sprintf(format, diff, unit)
+
+	if previous == 0 {
+		return change
+	}
+
+	return fmt.Sprintf("%s, %+.1f%%", change, diff/previous*100)
+}
@@ -0,0 +1,171 @@
+package bench
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// SendBatches returns the number of batch requests sent, and the number of transactions they contained
+func (b *Benchmark) SendBatches() (uint64, uint64) {
+	var batches, txs uint64
+	for _, batcher := range b.Batchers {
+		batches += batcher.Batches.Load()
+		txs += batcher.Transactions.Load()
+	}
+
+	return batches, txs
+}
+
+// a transaction waiting in a batch, and the channel its result is sent to
+type batchedSend struct {
+	tx   *solana.Transaction
+	done chan batchedResult
+}
+
+type batchedResult struct {
+	sig solana.Signature
+	err error
+}
+
+// SendBatcher groups the transactions sent concurrently through a client into JSON-RPC batch requests,
+// a batch is sent once full, or once its first transaction waited for SendBatchMaxWait
+type SendBatcher struct {
+	Client *rpc.Client
+	Size   int
+
+	// the batches in flight are cancelled along with it
+	ctx context.Context
+
+	mu      sync.Mutex
+	pending []*batchedSend
+	timer   *time.Timer
+
+	// the number of batch requests sent, and the number of transactions they contained
+	Batches      atomic.Uint64
+	Transactions atomic.Uint64
+}
+
+func NewSendBatcher(ctx context.Context, client *rpc.Client, size int) *SendBatcher {
+	return &SendBatcher{Client: client, Size: size, ctx: ctx}
+}
+
+// Send adds the transaction to the next batch, and waits until the batch is sent
+func (s *SendBatcher) Send(tx *solana.Transaction) (solana.Signature, error) {
+	item := &batchedSend{tx: tx, done: make(chan batchedResult, 1)}
+
+	s.mu.Lock()
+	s.pending = append(s.pending, item)
+
+	var full []*batchedSend
+	switch {
+	case len(s.pending) >= s.Size:
+		full = s.take()
+	case len(s.pending) == 1:
+		s.timer = time.AfterFunc(SendBatchMaxWait, s.flushPending)
+	}
+	s.mu.Unlock()
+
+	if full != nil {
+		go s.flush(full)
+	}
+
+	result := <-item.done
+	return result.sig, result.err
+}
+
+// take returns the pending transactions and starts a new batch, it must be called with the lock held
+func (s *SendBatcher) take() []*batchedSend {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	batch := s.pending
+	s.pending = nil
+
+	return batch
+}
+
+// flushPending sends the incomplete batch whose wait elapsed
+func (s *SendBatcher) flushPending() {
+	s.mu.Lock()
+	batch := s.take()
+	s.mu.Unlock()
+
+	if len(batch) > 0 {
+		s.flush(batch)
+	}
+}
+
+// flush sends the transactions as a single batch request, and passes each one its own result
+func (s *SendBatcher) flush(batch []*batchedSend) {
+	sendOpts := SendOpts()
+	opts := sendOpts.ToMap()
+
+	requests := jsonrpc.RPCRequests{}
+	sent := []*batchedSend{}
+	for _, item := range batch {
+		data, err := item.tx.MarshalBinary()
+		if err != nil {
+			item.done <- batchedResult{err: fmt.Errorf("error encoding tx: %w", err)}
+			continue
+		}
+
+		requests = append(requests, jsonrpc.NewRequest("sendTransaction", base64.StdEncoding.EncodeToString(data), opts))
+		sent = append(sent, item)
+	}
+
+	if len(requests) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, GlobalConfig.GetSendTimeout())
+	defer cancel()
+
+	s.Batches.Add(1)
+	s.Transactions.Add(uint64(len(requests)))
+
+	responses, err := s.Client.RPCCallBatch(ctx, requests)
+
+	// the requests are numbered in order by the client
+	byId := responses.AsMap()
+	for i, item := range sent {
+		item.done <- batchResult(byId[i], err)
+	}
+}
+
+// batchResult returns the result of a request of the batch, given the error of the whole batch
+func batchResult(response *jsonrpc.RPCResponse, err error) batchedResult {
+	if err != nil {
+		// keep the deadline detectable by the caller
+		if errors.Is(err, context.DeadlineExceeded) {
+			return batchedResult{err: context.DeadlineExceeded}
+		}
+		return batchedResult{err: err}
+	}
+
+	if response == nil {
+		return batchedResult{err: errors.New("missing response in the batch")}
+	}
+
+	if response.Error != nil {
+		return batchedResult{err: response.Error}
+	}
+
+	var sig solana.Signature
+	if err := json.Unmarshal(response.Result, &sig); err != nil {
+		return batchedResult{err: fmt.Errorf("error decoding signature: %w", err)}
+	}
+
+	return batchedResult{sig: sig}
+}
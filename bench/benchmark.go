@@ -0,0 +1,2015 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	addresslookuptable "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/time/rate"
+)
+
+type TxRecord struct {
+	Signature solana.Signature
+	Num       uint64
+	Wallet    solana.PublicKey
+	SendTime  time.Time
+
+	// the serialized size of the transaction in bytes
+	Size int
+
+	// the url the transaction was sent to, empty in jito send mode
+	SendUrl string
+
+	// the latest known slot when the transaction was sent, 0 if unknown
+	SendSlot uint64
+
+	// the slot the blockhash of the transaction was fetched at
+	BlockhashSlot uint64
+
+	// the time the RPC took to accept the transaction, i.e. for the send call to return
+	SendLatency time.Duration
+
+	// landing data, only set if the transaction landed
+	Landed   bool
+	LandTime time.Time
+	Delta    time.Duration
+	Slot     uint64
+
+	// the time it took to reach each commitment level, only set if track_all_commitments is enabled
+	CommitmentDeltas map[rpc.CommitmentType]time.Duration
+
+	// set if the block the transaction landed in was reorged out, only checked with verify_blocks
+	Reorged bool
+
+	// set if the landing was recovered by a status sweep,
+	// in which case the landing time and delta are unknown
+	Backfilled bool
+
+	// set if the landing time was before the send time, the delta is then clamped to 0
+	NegativeDelta bool
+
+	// the send rate of the ramp step the transaction was sent in, only set with rate_start and rate_end
+	RampRate uint64
+
+	// the compute unit price the transaction paid, in micro-lamports
+	ComputeUnitPrice uint64
+
+	// the last valid block height of the blockhash of the transaction
+	LastValidBlockHeight uint64
+
+	// the send attempt of the transaction number, starting at 1, and whether it was resent
+	// with a fresh blockhash once expired, only with max_resends
+	Attempt uint64
+	Resent  bool
+
+	// the span of the send to landing lifecycle, only set with otel_endpoint
+	span trace.Span
+}
+
+// HasLandingTime reports whether the transaction landed at a known time after it was sent
+func (r *TxRecord) HasLandingTime() bool {
+	return r.Landed && !r.Backfilled && !r.NegativeDelta && !r.SendTime.IsZero()
+}
+
+// Benchmark holds the state of a test run against a single endpoint
+type Benchmark struct {
+	Endpoint Endpoint
+
+	// the id tagging the transactions of this run, and the run number when the test is repeated
+	TestID    string
+	RunNumber uint64
+
+	Listener Listener
+	Poller   *StatusPoller
+
+	// records the time to reach every commitment level, only started if track_all_commitments is enabled
+	Lifecycle *LifecycleTracker
+
+	wg sync.WaitGroup
+	mu sync.RWMutex
+
+	// the rate limiter
+	Limiter *rate.Limiter
+
+	// builds the measured instruction of the transactions
+	Workload Workload
+
+	// the block engine client, only set in jito send mode
+	Jito *JitoClient
+
+	// the batchers of the send clients, only set if send_batch_size is greater than 1
+	Batchers map[*rpc.Client]*SendBatcher
+
+	// follows the current slot, nil if the slot subscription failed
+	Slots *SlotTracker
+
+	// the latest blockhash, periodically refreshed while sending,
+	// along with its last valid block height and the slot it was fetched at
+	blockhash            solana.Hash
+	lastValidBlockHeight uint64
+	blockhashSlot        uint64
+	blockhashMu          sync.RWMutex
+
+	// the slot each blockhash used by the run was fetched at, and its last valid block height
+	blockhashSlots   map[solana.Hash]uint64
+	blockhashHeights map[solana.Hash]uint64
+
+	// the address lookup tables available to v0 transactions
+	AddressTables map[solana.PublicKey]solana.PublicKeySlice
+
+	// the compute unit price used by this run, in micro-lamports
+	// it may differ from the config in dynamic mode
+	ComputeUnitPrice uint64
+
+	// the time the run started and finished
+	StartTime time.Time
+	EndTime   time.Time
+
+	// the estimated time the run should end, only used when the slots can't be followed
+	StopTime time.Time
+
+	// the aligned time the transactions start being sent,
+	// and in duration mode, the time to stop sending new ones
+	SpamStartTime time.Time
+	SendDeadline  time.Time
+
+	// the time the last transaction was sent, and the last one landed
+	LastSendTime time.Time
+	LastLandTime time.Time
+
+	// tracks the transactions being sent, and whether they're all sent
+	sendWg      sync.WaitGroup
+	SendingDone bool
+
+	// the number of transactions sent and transactions that landed
+	SentTransactions      uint64
+	ProcessedTransactions uint64
+
+	// the number of transactions built but not sent in dry run mode
+	DryRunTransactions uint64
+
+	// the number of times a transaction was rate limited by the RPC
+	RateLimitedTransactions uint64
+
+	// the time the transactions waited for the rate limiter
+	Throttle ThrottleStats
+
+	// the current send rate backoff level, and the time of the last backoff
+	backoffLevel uint
+	lastBackoff  time.Time
+
+	// the send rate of the current step of the ramp, before any backoff, only set with rate_start and rate_end
+	rampRate uint64
+
+	// the number of expired transactions resent, the resends not recorded yet,
+	// and the attempt of the resend of each transaction number, only with max_resends
+	ResentTransactions uint64
+	resendsInFlight    uint64
+	resendAttempts     map[uint64]uint64
+
+	// the number of transactions whose submission timed out, they're not part of the sent ones
+	TimedOutTransactions uint64
+
+	// the number of transactions rejected by the preflight simulation, they're not part of the sent ones
+	PreflightFailedTransactions uint64
+
+	// set when the run was stopped by the early abort check
+	AbortedEarly bool
+
+	// the number of landings seen before the send time, kept out of the landing times
+	NegativeDeltas uint64
+
+	// the number of landings checked once finalized, and the ones on blocks reorged out,
+	// some of which landed again in another slot, only set if verify_blocks is enabled
+	LandingsVerified   uint64
+	ReorgedLandings    uint64
+	ReincludedLandings uint64
+
+	// the number of transactions that failed to be sent, by error category
+	SendErrors map[string]uint64
+
+	// the number of warmup transactions sent, they're not part of the results
+	WarmupTransactions uint64
+
+	// delta between transaction send times and landing times
+	TxDeltas []time.Duration
+
+	// number of slots between the send slots and the landing slots
+	TxSlotDistances []uint64
+
+	// number of slots between the blockhash slots and the landing slots
+	TxInclusionOffsets []uint64
+
+	// time the RPC took to accept each transaction
+	TxSendLatencies []time.Duration
+
+	// with streaming_stats, the landing times, slot distances and send latencies are summarized in these
+	// sketches instead of the slices above, along with the landing time histogram counts
+	DeltaSketch           *Sketch
+	SlotDistanceSketch    *Sketch
+	InclusionOffsetSketch *Sketch
+	SendLatencySketch     *Sketch
+	LatencyCounts         []uint64
+
+	// delta between transaction send times and the times they reached each commitment level
+	CommitmentDeltas map[rpc.CommitmentType][]time.Duration
+
+	// blocks where transactions landed
+	TxBlocks map[uint64]uint64
+
+	// the leaders of the slots spanned by the landings, unset if the leader schedule couldn't be fetched
+	SlotLeaders map[uint64]solana.PublicKey
+
+	// the commitment reached by the blocks where transactions landed, only set if verify_blocks is enabled
+	BlockStatuses map[uint64]string
+
+	// per-transaction records, correlating send and landing data
+	TxRecords map[solana.Signature]*TxRecord
+
+	// with streaming_stats, the records are dropped once written to the csv file and summed up here,
+	// unless held until the early landing rate was checked, the records left at the end of the run
+	// are written along with the results
+	Streamed      *StreamedRecords
+	holdRecords   bool
+	streamStopped bool
+
+	// the second websocket the landings are cross-checked with, only set with cross_check_ws_url
+	CrossCheck *CrossCheckListener
+
+	// the stream of transactions sent at a known-good fee alongside the workload, only set with control_rate
+	Control *ControlStream
+
+	// the span of the run and its context, the parent of the spans of the transactions, only set with otel_endpoint
+	runSpan  trace.Span
+	traceCtx context.Context
+
+	// closed when the run is stopped, to end the progress reports
+	stopped  chan struct{}
+	stopOnce sync.Once
+
+	// cancelled once the run is stopped, which aborts the sends and the requests in flight,
+	// the requests made once the run is over, e.g. to wait for the finalization, use the test context
+	ctx     context.Context
+	cancel  context.CancelFunc
+	testCtx context.Context
+}
+
+// NewBenchmark creates a run against the endpoint, the cancellation of the test context stops it
+func NewBenchmark(ctx context.Context, endpoint Endpoint, testID string, run uint64) *Benchmark {
+	b := &Benchmark{
+		Endpoint:           endpoint,
+		TestID:             testID,
+		RunNumber:          run,
+		Limiter:            rate.NewLimiter(rate.Limit(GlobalConfig.GetRateLimit()), int(GlobalConfig.GetBurst())),
+		ComputeUnitPrice:   GlobalConfig.GetComputeUnitPrice(),
+		Workload:           NewWorkload(GlobalConfig),
+		TxDeltas:           []time.Duration{},
+		TxBlocks:           make(map[uint64]uint64),
+		TxRecords:          make(map[solana.Signature]*TxRecord),
+		TxSlotDistances:    []uint64{},
+		TxInclusionOffsets: []uint64{},
+		TxSendLatencies:    []time.Duration{},
+		CommitmentDeltas:   make(map[rpc.CommitmentType][]time.Duration),
+		SendErrors:         make(map[string]uint64),
+		blockhashSlots:     make(map[solana.Hash]uint64),
+		blockhashHeights:   make(map[solana.Hash]uint64),
+		resendAttempts:     make(map[uint64]uint64),
+		stopped:            make(chan struct{}),
+		testCtx:            ctx,
+	}
+	b.ctx, b.cancel = context.WithCancel(ctx)
+	b.Listener = NewListener(b)
+	b.Poller = NewStatusPoller(b)
+	b.Lifecycle = NewLifecycleTracker(b)
+
+	if GlobalConfig.ControlRate > 0 {
+		b.Control = NewControlStream(b)
+	}
+
+	// the ramp starts at its first step
+	if GlobalConfig.IsRamp() {
+		b.rampRate = GlobalConfig.RampRate(0)
+		b.applyBackoff()
+	}
+
+	if GlobalConfig.StreamingStats {
+		b.DeltaSketch = NewSketch()
+		b.SlotDistanceSketch = NewSketch()
+		b.InclusionOffsetSketch = NewSketch()
+		b.SendLatencySketch = NewSketch()
+		b.LatencyCounts = make([]uint64, MaxHistogramBuckets)
+		b.Streamed = NewStreamedRecords()
+		b.holdRecords = GlobalConfig.EarlyAbortWindow > 0 && !GlobalConfig.DryRun
+	}
+
+	return b
+}
+
+// Label identifies the run in the comparison table and the csv file
+func (b *Benchmark) Label() string {
+	if GlobalConfig.GetRepeat() > 1 {
+		return fmt.Sprintf("%s #%d", b.Endpoint.GetLabel(), b.RunNumber)
+	}
+
+	return b.Endpoint.GetLabel()
+}
+
+// Run starts the confirmation sources of the configured confirm mode,
+// sends the transactions and blocks until the run is over, it fails if the run couldn't be started
+func (b *Benchmark) Run() error {
+	b.StartTime = time.Now()
+	b.startRunSpan()
+
+	// the test may be interrupted at any point of the run
+	stopOnCancel := context.AfterFunc(b.testCtx, b.Stop)
+	defer stopOnCancel()
+
+	// in send only mode, the landings are recorded by the listen only side
+	sendOnly := GlobalConfig.SplitMode == SplitModeSendOnly
+
+	if GlobalConfig.GetConfirmMode() != ConfirmModePoll && !sendOnly {
+		if err := b.Listener.Connect(); err != nil {
+			return b.abort(err)
+		}
+
+		b.wg.Add(1)
+		go b.Listener.Start()
+	}
+
+	if GlobalConfig.CrossCheckWsUrl != "" {
+		crossCheck := NewCrossCheckListener(GlobalConfig.CrossCheckWsUrl)
+		if err := crossCheck.Connect(b.ctx); err != nil {
+			return b.abort(err)
+		}
+
+		b.CrossCheck = crossCheck
+		b.CrossCheck.Start()
+	}
+
+	if GlobalConfig.GetConfirmMode() != ConfirmModeWs && !sendOnly {
+		b.Poller.Polling = true
+
+		b.wg.Add(1)
+		go b.Poller.Start()
+	}
+
+	if GlobalConfig.TrackAllCommitments {
+		b.wg.Add(1)
+		go b.Lifecycle.Start()
+	}
+
+	go b.ReportProgress()
+
+	if GlobalConfig.EarlyAbortWindow > 0 && !GlobalConfig.DryRun {
+		go b.WatchEarlyAbort()
+	}
+
+	if GlobalConfig.SplitMode == SplitModeListenOnly {
+		log.Info("Listening for the transactions of the test, sent from another machine", "test_id", b.TestID)
+		go b.WatchListenIdle()
+	} else {
+		// start sending transactions now that the listeners are ready
+		if err := b.SendTransactions(); err != nil {
+			return b.abort(err)
+		}
+	}
+
+	// without a listener, the run is over once every transaction was submitted
+	if sendOnly {
+		b.sendWg.Wait()
+		b.Stop()
+	}
+
+	b.wg.Wait()
+
+	// the late notifications of the cross-check websocket are ignored like the ones of the endpoint
+	if b.CrossCheck != nil {
+		b.CrossCheck.Stop()
+	}
+
+	// the control transactions may outlive the workload, their statuses are fetched once they settled
+	if b.Control != nil {
+		b.Control.Sweep(b.testCtx, NewRpcClient(b.Endpoint.RpcUrl))
+	}
+
+	// the listener may have given up without stopping the run, end the progress reports
+	b.stopOnce.Do(func() { close(b.stopped) })
+	b.cancel()
+
+	if b.Slots != nil {
+		b.Slots.Stop()
+	}
+
+	b.EndTime = time.Now()
+	b.endRunSpan()
+
+	if !GlobalConfig.DryRun {
+		b.FetchSlotLeaders(b.testCtx)
+	}
+
+	// the wait for the finalization doesn't count in the run duration
+	if GlobalConfig.VerifyBlocks && !GlobalConfig.DryRun {
+		b.VerifyBlocks(b.testCtx)
+	}
+
+	b.stopStreaming()
+
+	return nil
+}
+
+// abort stops what was started of a run that failed to start, and returns the error
+func (b *Benchmark) abort(err error) error {
+	b.Stop()
+	b.wg.Wait()
+
+	if b.CrossCheck != nil {
+		b.CrossCheck.Stop()
+	}
+
+	b.EndTime = time.Now()
+	b.endRunSpan()
+	b.stopStreaming()
+
+	return err
+}
+
+// Stop stops the listener and the poller, which ends the run, and cancels the requests in flight
+func (b *Benchmark) Stop() {
+	b.Listener.Stop()
+	b.Poller.Stop()
+	b.stopOnce.Do(func() { close(b.stopped) })
+	b.cancel()
+}
+
+// IsRunning reports whether the landings are still being watched
+func (b *Benchmark) IsRunning() bool {
+	// without a listener, the run goes on until stopped
+	if GlobalConfig.SplitMode == SplitModeSendOnly {
+		select {
+		case <-b.stopped:
+			return false
+		default:
+			return true
+		}
+	}
+
+	return b.Listener.IsListening() || b.Poller.Polling
+}
+
+// ResolvePriorityFee sets the priority fee to the configured percentile of the recent prioritization fees
+func (b *Benchmark) ResolvePriorityFee(rpcClient *rpc.Client) error {
+	price, slots, err := RecentFeePercentile(b.ctx, rpcClient)
+	if err != nil {
+		return err
+	}
+
+	if slots == 0 {
+		log.Warn("No recent prioritization fees available, using the static compute unit price", "cu_price", b.ComputeUnitPrice)
+		return nil
+	}
+
+	b.ComputeUnitPrice = price
+
+	log.Info(
+		"Resolved dynamic priority fee",
+		"percentile", GlobalConfig.GetPrioFeePercentile(),
+		"slots", slots,
+		"cu_price", fmt.Sprintf("%d micro-lamports", b.ComputeUnitPrice),
+	)
+
+	return nil
+}
+
+// ResolveLookupTable fetches the addresses of the configured address lookup table
+func (b *Benchmark) ResolveLookupTable(rpcClient *rpc.Client) error {
+	tableKey := solana.MustPublicKeyFromBase58(GlobalConfig.LookupTable)
+
+	table, err := addresslookuptable.GetAddressLookupTable(b.ctx, rpcClient, tableKey)
+	if err != nil {
+		return fmt.Errorf("error getting address lookup table: %w", err)
+	}
+
+	b.AddressTables = map[solana.PublicKey]solana.PublicKeySlice{tableKey: table.Addresses}
+
+	log.Info("Resolved address lookup table", "table", tableKey, "addresses", len(table.Addresses))
+
+	return nil
+}
+
+// WalletFor returns the wallet paying for the transaction with the given number,
+// the transactions are spread across the test wallets in a round robin fashion
+func WalletFor(id uint64) *solana.PrivateKey {
+	return TestAccounts[(id-1)%uint64(len(TestAccounts))]
+}
+
+// SendIndexFor returns the index of the send url of the transaction with the given number,
+// the transactions are spread across the send urls in a round robin fashion
+func SendIndexFor(id uint64, count int) int {
+	return int((id - 1) % uint64(count))
+}
+
+func (b *Benchmark) BuildTransaction(id uint64, blockhash solana.Hash) *solana.Transaction {
+	wallet := WalletFor(id)
+	return b.buildTransaction(b.Workload.Instruction(id, b.TestID, wallet.PublicKey()), blockhash, wallet, b.ComputeUnitPriceFor(id))
+}
+
+// ComputeUnitPriceFor returns the compute unit price of the transaction with the given number,
+// the price of its step with fee_min and fee_max, or else the price of the run
+func (b *Benchmark) ComputeUnitPriceFor(id uint64) uint64 {
+	if GlobalConfig.IsFeeSweep() {
+		return GlobalConfig.FeeLevel(GlobalConfig.FeeStepFor(id))
+	}
+
+	return b.ComputeUnitPrice
+}
+
+// BuildWarmupTransaction builds a throwaway memo transaction, tagged so that the listener ignores it
+func (b *Benchmark) BuildWarmupTransaction(id uint64, blockhash solana.Hash) *solana.Transaction {
+	wallet := WalletFor(id)
+	memo := fmt.Sprintf("%s %d [%s]", WarmupMemoPrefix, id, b.TestID)
+	return b.buildTransaction(MemoInstruction(GlobalConfig.GetMemoProgramId(), memo, wallet.PublicKey()), blockhash, wallet, b.ComputeUnitPrice)
+}
+
+func (b *Benchmark) buildTransaction(instruction solana.Instruction, blockhash solana.Hash, wallet *solana.PrivateKey, computeUnitPrice uint64) *solana.Transaction {
+	tx := b.assembleTransaction(instruction, nil, blockhash, wallet, computeUnitPrice)
+
+	// pad the transaction up to the target size with a memo, the size follows the memo length,
+	// the extra attempts adjust for the length prefix taking an extra byte past 127 bytes
+	target := int(GlobalConfig.TxSizeBytes)
+	if target == 0 || TransactionSize(tx) >= target {
+		return tx
+	}
+
+	padding := 1
+	for attempt := 0; attempt < 3; attempt++ {
+		padded := b.assembleTransaction(instruction, PaddingInstruction(padding), blockhash, wallet, computeUnitPrice)
+
+		// keep the unpadded transaction if even the smallest padding exceeds the target
+		size := TransactionSize(padded)
+		if size <= target {
+			tx = padded
+		}
+		if size == target || size > target && padding == 1 {
+			break
+		}
+
+		padding = max(padding+target-size, 1)
+	}
+
+	return tx
+}
+
+// assembleTransaction builds and signs the transaction at the given compute unit price, with an optional padding instruction
+func (b *Benchmark) assembleTransaction(instruction solana.Instruction, padding solana.Instruction, blockhash solana.Hash, wallet *solana.PrivateKey, computeUnitPrice uint64) *solana.Transaction {
+	instructions := []solana.Instruction{}
+
+	if computeUnitPrice > 0 {
+		instructions = append(instructions, computebudget.NewSetComputeUnitPriceInstruction(computeUnitPrice).Build())
+		instructions = append(instructions, computebudget.NewSetComputeUnitLimitInstruction(GlobalConfig.GetComputeUnitLimit()).Build())
+	}
+
+	instructions = append(instructions, instruction)
+
+	if padding != nil {
+		instructions = append(instructions, padding)
+	}
+
+	// bundles are only considered by the block engine if they pay a tip
+	if b.Jito != nil {
+		instructions = append(instructions, b.Jito.TipInstruction(GlobalConfig.JitoTip, wallet.PublicKey()))
+	}
+
+	opts := []solana.TransactionOption{solana.TransactionPayer(wallet.PublicKey())}
+	if len(b.AddressTables) > 0 {
+		opts = append(opts, solana.TransactionAddressTables(b.AddressTables))
+	}
+
+	tx, err := solana.NewTransaction(instructions, blockhash, opts...)
+	if err != nil {
+		log.Fatalf("error creating new transaction: %v", err)
+	}
+
+	// the transaction is only built as v0 when it uses a lookup table, force it otherwise
+	if GlobalConfig.GetTxVersion() == TxVersionV0 {
+		tx.Message.SetVersion(solana.MessageVersionV0)
+	}
+
+	_, err = tx.Sign(
+		func(key solana.PublicKey) *solana.PrivateKey {
+			if wallet.PublicKey().Equals(key) {
+				return wallet
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		log.Fatalf("error signing new transaction: %v", err)
+	}
+
+	return tx
+}
+
+// TransactionSize returns the serialized size of the transaction in bytes
+func TransactionSize(tx *solana.Transaction) int {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		log.Fatalf("error encoding tx: %v", err)
+	}
+
+	return len(data)
+}
+
+func SendOpts() rpc.TransactionOpts {
+	return rpc.TransactionOpts{
+		Encoding:            solana.EncodingBase64,
+		SkipPreflight:       GlobalConfig.GetSkipPreflight(),
+		PreflightCommitment: rpc.CommitmentType(GlobalConfig.PreflightCommitment),
+		MaxRetries:          &GlobalConfig.NodeRetries,
+	}
+}
+
+// Send submits the transaction through the configured send mode, giving up after the send timeout,
+// in jito mode the transaction is sent as a single transaction bundle
+func (b *Benchmark) Send(sendClient *rpc.Client, tx *solana.Transaction) (solana.Signature, error) {
+	ctx, cancel := context.WithTimeout(b.ctx, GlobalConfig.GetSendTimeout())
+	defer cancel()
+
+	if batcher, ok := b.Batchers[sendClient]; ok {
+		return batcher.Send(tx)
+	}
+
+	if b.Jito == nil {
+		return sendClient.SendTransactionWithOpts(ctx, tx, SendOpts())
+	}
+
+	bundleId, err := b.Jito.SendBundle(ctx, tx)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+
+	log.Debug("Bundle accepted", "bundle", bundleId, "sig", tx.Signatures[0])
+
+	return tx.Signatures[0], nil
+}
+
+// SendWarmup sends the warmup transactions and waits until they're all submitted,
+// they're not recorded, and only serve to warm up the connections to the RPC
+func (b *Benchmark) SendWarmup(sendClients []*rpc.Client, blockhash solana.Hash) {
+	log.Info("Sending warmup transactions", "count", GlobalConfig.WarmupTxCount)
+
+	var wg sync.WaitGroup
+	for id := uint64(1); id <= GlobalConfig.WarmupTxCount; id++ {
+		// the wait is cancelled once the run is stopped
+		if err := b.Limiter.Wait(b.ctx); err != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+
+			tx := b.BuildWarmupTransaction(id, blockhash)
+			if GlobalConfig.DryRun {
+				log.Info("Dry run, not sending warmup tx", "num", id, "sig", tx.Signatures[0])
+				return
+			}
+
+			if _, err := b.Send(sendClients[SendIndexFor(id, len(sendClients))], tx); err != nil {
+				log.Warn("Error sending warmup tx", "err", err)
+				return
+			}
+
+			b.mu.Lock()
+			b.WarmupTransactions += 1
+			b.mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	log.Info("Warmup done", "sent", fmt.Sprintf("%d/%d", b.WarmupTransactions, GlobalConfig.WarmupTxCount))
+}
+
+func (b *Benchmark) SubmitTransaction(sendClients []*rpc.Client, id uint64, tx *solana.Transaction) {
+	// the run was stopped meanwhile, e.g. aborted early
+	if !b.IsRunning() {
+		return
+	}
+
+	log.Debugf("Sending Tx [%s]", tx.Signatures[0])
+
+	// the transaction is built and signed, but never sent
+	if GlobalConfig.DryRun {
+		log.Info("Dry run, not sending tx", "num", id, "sig", tx.Signatures[0], "size", TransactionSize(tx))
+
+		b.mu.Lock()
+		b.DryRunTransactions += 1
+		b.mu.Unlock()
+		return
+	}
+
+	var sendSlot uint64
+	if b.Slots != nil {
+		sendSlot = b.Slots.Slot()
+	}
+
+	// spread the transactions across the send urls
+	index := SendIndexFor(id, len(sendClients))
+	sendClient := sendClients[index]
+
+	var sendUrl string
+	if GlobalConfig.GetSendMode() == SendModeRpc {
+		sendUrl = b.Endpoint.GetSendUrls()[index]
+	}
+
+	// only the last attempt is timed, without the backoff
+	sendStart := time.Now()
+	sig, err := b.Send(sendClient, tx)
+
+	// back off and retry the rate limited transactions
+	for attempt := 1; err != nil && IsRateLimitError(err) && attempt <= MaxRateLimitRetries; attempt++ {
+		b.Backoff()
+
+		if err := b.Limiter.Wait(b.ctx); err != nil {
+			return
+		}
+
+		log.Debug("Retrying rate limited tx", "num", id, "attempt", attempt, "sig", tx.Signatures[0])
+		sendStart = time.Now()
+		sig, err = b.Send(sendClient, tx)
+	}
+	sendLatency := time.Since(sendStart)
+
+	// the send was cancelled by the end of the run, it may or may not have reached the RPC
+	if err != nil && b.ctx.Err() != nil {
+		log.Debug("Tx send cancelled, the run is over", "num", id, "sig", tx.Signatures[0])
+		return
+	}
+
+	if err != nil {
+		b.RecordSendError(err)
+
+		// the transaction may or may not have reached the RPC
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Errorf("Error sending tx: Timed out after %s [%s]", GlobalConfig.GetSendTimeout(), tx.Signatures[0])
+
+			b.mu.Lock()
+			b.TimedOutTransactions += 1
+			b.mu.Unlock()
+			return
+		}
+
+		if val, ok := err.(*jsonrpc.RPCError); ok {
+			// the simulation error and logs are only returned when preflight is enabled
+			if val.Code == PreflightFailureCode {
+				b.LogPreflightFailure(id, tx.Signatures[0], val)
+
+				b.mu.Lock()
+				b.PreflightFailedTransactions += 1
+				b.mu.Unlock()
+				return
+			}
+
+			log.Errorf("Error sending tx: Received RPC error: %s", val.Message)
+			return
+		}
+
+		log.Errorf("Error sending tx: %v", err)
+		return
+	}
+
+	// save the tx send time for later comparison
+	b.mu.Lock()
+	sendTime := time.Now()
+	record := &TxRecord{Signature: sig, Num: id, Wallet: tx.Message.AccountKeys[0], SendTime: sendTime, Size: TransactionSize(tx), SendUrl: sendUrl, SendSlot: sendSlot, BlockhashSlot: b.BlockhashSlot(tx.Message.RecentBlockhash), SendLatency: sendLatency, RampRate: b.rampRate, ComputeUnitPrice: b.ComputeUnitPriceFor(id), LastValidBlockHeight: b.BlockhashLastValidHeight(tx.Message.RecentBlockhash), Attempt: max(b.resendAttempts[id], 1)}
+	if GlobalConfig.TrackAllCommitments {
+		record.CommitmentDeltas = make(map[rpc.CommitmentType]time.Duration)
+	}
+	b.TxRecords[sig] = record
+	b.SentTransactions += 1
+	b.LastSendTime = sendTime
+	b.recordSendLatency(sendLatency)
+	b.startTxSpan(record, sendStart)
+	b.mu.Unlock()
+
+	b.WriteSentEvent(sig, id, sendTime)
+
+	SentCounter.WithLabelValues(b.TestID, b.Endpoint.GetLabel()).Inc()
+
+	// the signature listener subscribes to each transaction once sent
+	if watcher, ok := b.Listener.(SignatureWatcher); ok && GlobalConfig.GetConfirmMode() != ConfirmModePoll {
+		watcher.Watch(sig)
+	}
+}
+
+// LogPreflightFailure logs the simulation error of a transaction rejected by the preflight checks
+func (b *Benchmark) LogPreflightFailure(id uint64, sig solana.Signature, rpcErr *jsonrpc.RPCError) {
+	var simulationErr interface{}
+	var logs []interface{}
+	if data, ok := rpcErr.Data.(map[string]interface{}); ok {
+		simulationErr = data["err"]
+		logs, _ = data["logs"].([]interface{})
+	}
+
+	log.Error("Preflight simulation failed", "num", id, "sig", sig, "msg", rpcErr.Message, "err", simulationErr)
+	for _, line := range logs {
+		log.Debug("Preflight simulation log", "num", id, "log", line)
+	}
+}
+
+// SendTransactions starts sending the transactions of the run, it fails if the sends can't be prepared
+func (b *Benchmark) SendTransactions() error {
+	// Create a new RPC client:
+	rpcClient := NewRpcClient(b.Endpoint.RpcUrl)
+
+	// create the send clients, the transactions are spread across them
+	sendClients := []*rpc.Client{}
+	for _, sendUrl := range b.Endpoint.GetSendUrls() {
+		sendClients = append(sendClients, NewRpcClient(sendUrl))
+	}
+
+	// group the concurrent sends into batch requests
+	if GlobalConfig.SendBatchSize > 1 {
+		b.Batchers = make(map[*rpc.Client]*SendBatcher)
+		for _, sendClient := range sendClients {
+			b.Batchers[sendClient] = NewSendBatcher(b.ctx, sendClient, int(GlobalConfig.SendBatchSize))
+		}
+	}
+
+	// in jito mode, the transactions are sent to the block engine instead
+	if GlobalConfig.GetSendMode() == SendModeJito {
+		b.Jito = NewJitoClient(GlobalConfig.JitoUrl)
+		if err := b.Jito.FetchTipAccounts(b.ctx); err != nil {
+			return err
+		}
+	}
+
+	// load the lookup table used by the v0 transactions
+	if GlobalConfig.LookupTable != "" {
+		if err := b.ResolveLookupTable(rpcClient); err != nil {
+			return err
+		}
+	}
+
+	// resolve the priority fee from the recent fees if needed
+	if GlobalConfig.GetPrioFeeMode() == PrioFeeModeDynamic {
+		if err := b.ResolvePriorityFee(rpcClient); err != nil {
+			return err
+		}
+	}
+
+	// fetch the latest blockhash
+	recent, err := RetryRpc(b.ctx, "getLatestBlockhash", func(ctx context.Context) (*rpc.GetLatestBlockhashResult, error) {
+		return rpcClient.GetLatestBlockhash(ctx, GlobalConfig.GetBlockhashCommitment())
+	})
+	if err != nil {
+		return fmt.Errorf("error getting recent blockhash: %w", err)
+	}
+
+	b.SetBlockhash(recent.Value.Blockhash, recent.Value.LastValidBlockHeight, recent.Context.Slot)
+
+	// follow the current slot to record the send slots, the websocket isn't used in send only mode
+	if GlobalConfig.SplitMode != SplitModeSendOnly {
+		slots, err := NewSlotTracker(b.ctx, b.Endpoint.GetWsUrl(), recent.Context.Slot)
+		if err != nil {
+			log.Warn("Unable to follow the current slot, the slot landing distances will not be reported", "err", err)
+		} else {
+			b.Slots = slots
+			go b.Slots.Start()
+		}
+	}
+
+	// warm up the connections before the measured batch
+	if GlobalConfig.WarmupTxCount > 0 {
+		b.SendWarmup(sendClients, b.LatestBlockhash())
+
+		// the warmup batches aren't part of the results
+		for _, batcher := range b.Batchers {
+			batcher.Batches.Store(0)
+			batcher.Transactions.Store(0)
+		}
+	}
+
+	// the run may have been interrupted meanwhile
+	if !b.IsRunning() {
+		return nil
+	}
+
+	// sleep until the start time; then start spamming the transactions
+	b.SpamStartTime = GlobalConfig.GetSpamStartTime(time.Now())
+
+	// estimate the experiment end time, in case the slots can't be followed
+	// each block is about 400ms, with a margin out of abundance of caution
+	expiry := time.Duration(BlockhashValidBlocks+BlockhashExpiryMargin) * SlotDuration
+	b.StopTime = time.Now().Add(GlobalConfig.GetSendWindow() + expiry)
+
+	// in duration mode, keep listening for the same window after the last send
+	if GlobalConfig.Duration > 0 {
+		b.SendDeadline = b.SpamStartTime.Add(time.Duration(GlobalConfig.Duration) * time.Second)
+		b.StopTime = b.SendDeadline.Add(expiry)
+	}
+
+	// stop the run once the blockhash of the last transactions expired,
+	// or once the last resends landed or expired when resending
+	if GlobalConfig.MaxResends > 0 {
+		go b.WatchResends(rpcClient, sendClients)
+	} else {
+		go b.WatchExpiry(rpcClient)
+	}
+
+	// keep the blockhash fresh for the late transactions
+	go b.RefreshBlockhash(rpcClient)
+
+	// the control stream runs alongside the workload, through the first send url
+	if b.Control != nil {
+		b.Control.Start(sendClients[0])
+	}
+
+	if GlobalConfig.Duration > 0 {
+		b.sendWg.Add(1)
+		go func() {
+			defer b.sendWg.Done()
+
+			submit := func(id uint64) {
+				b.SubmitTransaction(sendClients, id, b.BuildTransaction(id, b.LatestBlockhash()))
+			}
+
+			// with a worker pool, the transactions are handed to the workers instead of their own goroutine
+			var ids chan<- uint64
+			if GlobalConfig.Workers > 0 {
+				ids = b.StartSendWorkers(GlobalConfig.Workers, submit)
+				defer close(ids)
+			}
+
+			sleepTime := time.Until(b.SpamStartTime)
+			log.Info("Sleeping until starting spam", "delay", sleepTime.Truncate(time.Millisecond), "duration", time.Until(b.SendDeadline).Truncate(time.Second))
+			if !b.Sleep(sleepTime) {
+				return
+			}
+
+			if GlobalConfig.IsRamp() {
+				go b.RampUp()
+			}
+
+			// keep spawning transactions until the deadline, the rate limiter paces the sends,
+			// its wait is cancelled once the run is stopped
+			for id := uint64(1); time.Now().Before(b.SendDeadline); id++ {
+				if err := b.Limiter.Wait(b.ctx); err != nil {
+					return
+				}
+
+				if ids != nil {
+					ids <- id
+					continue
+				}
+
+				b.sendWg.Add(1)
+				go func(id uint64) {
+					defer b.sendWg.Done()
+					submit(id)
+				}(id)
+			}
+		}()
+	} else {
+		send := func(id uint64) {
+			blockhash := b.LatestBlockhash()
+			tx := b.BuildTransaction(id, blockhash)
+
+			// spread the threads evenly across the send window, if any
+			offset := GlobalConfig.GetSendWindow() * time.Duration(id-1) / time.Duration(GlobalConfig.TxCount)
+			sleepTime := time.Until(b.SpamStartTime.Add(offset))
+
+			// only log the first time, to avoid spamming logs
+			if id == 1 {
+				log.Info("Threads sleeping until starting spam", "delay", sleepTime.Truncate(time.Millisecond), "window", GlobalConfig.GetSendWindow())
+			}
+
+			if !b.Sleep(sleepTime) {
+				return
+			}
+
+			t0 := time.Now()
+			if err := b.Limiter.Wait(b.ctx); err != nil {
+				return
+			}
+
+			// log if the thread had to throttle to keep under the rate limit
+			throttleTime := time.Since(t0)
+			b.recordThrottle(throttleTime)
+			if throttleTime >= time.Millisecond {
+				log.Debug("Thread throttled to respect rate-limit, Sending now", "thread", id, "delay", throttleTime.Truncate(time.Millisecond))
+			}
+
+			// rebuild the transaction if the blockhash was refreshed meanwhile
+			if latest := b.LatestBlockhash(); latest != blockhash {
+				tx = b.BuildTransaction(id, latest)
+			}
+
+			b.SubmitTransaction(sendClients, id, tx)
+		}
+
+		if GlobalConfig.Workers > 0 {
+			// a fixed pool of workers sends the transactions in order
+			ids := b.StartSendWorkers(min(GlobalConfig.Workers, GlobalConfig.TxCount), send)
+			go func() {
+				defer close(ids)
+				for id := uint64(1); id <= GlobalConfig.TxCount; id++ {
+					select {
+					case ids <- id:
+					case <-b.ctx.Done():
+						return
+					}
+				}
+			}()
+		} else {
+			for i := uint64(0); i < GlobalConfig.TxCount; i++ {
+				b.sendWg.Add(1)
+				go func(id uint64) {
+					defer b.sendWg.Done()
+					send(id)
+				}(i + 1)
+			}
+		}
+	}
+
+	// flag the end of the sending phase once every transaction was submitted
+	go func() {
+		b.sendWg.Wait()
+
+		b.mu.Lock()
+		b.SendingDone = true
+		b.mu.Unlock()
+
+		if b.AllTransactionsLanded() {
+			b.Stop()
+		}
+	}()
+
+	return nil
+}
+
+// StartSendWorkers starts a pool of workers passing the transaction ids received on the returned channel to send,
+// the channel must be closed once all the ids are passed, the workers are tracked by the send wait group
+func (b *Benchmark) StartSendWorkers(count uint64, send func(id uint64)) chan<- uint64 {
+	ids := make(chan uint64)
+
+	for range count {
+		b.sendWg.Add(1)
+		go func() {
+			defer b.sendWg.Done()
+
+			for id := range ids {
+				send(id)
+			}
+		}()
+	}
+
+	return ids
+}
+
+// Sleep waits for the duration, it returns false if the run was stopped meanwhile
+func (b *Benchmark) Sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-b.ctx.Done():
+		return false
+	}
+}
+
+func (b *Benchmark) LatestBlockhash() solana.Hash {
+	b.blockhashMu.RLock()
+	defer b.blockhashMu.RUnlock()
+
+	return b.blockhash
+}
+
+func (b *Benchmark) SetBlockhash(blockhash solana.Hash, lastValidBlockHeight uint64, slot uint64) {
+	b.blockhashMu.Lock()
+	defer b.blockhashMu.Unlock()
+
+	b.blockhash = blockhash
+	b.lastValidBlockHeight = lastValidBlockHeight
+	b.blockhashSlot = slot
+	b.blockhashSlots[blockhash] = slot
+	b.blockhashHeights[blockhash] = lastValidBlockHeight
+}
+
+// BlockhashLastValidHeight returns the last valid block height of the blockhash, 0 if unknown
+func (b *Benchmark) BlockhashLastValidHeight(blockhash solana.Hash) uint64 {
+	b.blockhashMu.RLock()
+	defer b.blockhashMu.RUnlock()
+
+	return b.blockhashHeights[blockhash]
+}
+
+// BlockhashSlot returns the slot the blockhash was fetched at, 0 if unknown
+func (b *Benchmark) BlockhashSlot(blockhash solana.Hash) uint64 {
+	b.blockhashMu.RLock()
+	defer b.blockhashMu.RUnlock()
+
+	return b.blockhashSlots[blockhash]
+}
+
+// BlockhashExpiry returns the last valid block height of the latest blockhash, and the slot it was fetched at
+func (b *Benchmark) BlockhashExpiry() (uint64, uint64) {
+	b.blockhashMu.RLock()
+	defer b.blockhashMu.RUnlock()
+
+	return b.lastValidBlockHeight, b.blockhashSlot
+}
+
+// RefreshBlockhash periodically fetches the latest blockhash until all the transactions are sent,
+// so that the transactions sent late in the run don't use an expired blockhash
+func (b *Benchmark) RefreshBlockhash(rpcClient *rpc.Client) {
+	ticker := time.NewTicker(GlobalConfig.GetBlockhashRefreshInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// the resends need a fresh blockhash until the end of the run
+		b.mu.RLock()
+		done := b.SendingDone
+		b.mu.RUnlock()
+
+		if done && GlobalConfig.MaxResends == 0 {
+			return
+		}
+
+		recent, err := rpcClient.GetLatestBlockhash(b.ctx, GlobalConfig.GetBlockhashCommitment())
+		if err != nil {
+			log.Warn("Unable to refresh the blockhash", "err", err)
+			continue
+		}
+
+		b.SetBlockhash(recent.Value.Blockhash, recent.Value.LastValidBlockHeight, recent.Context.Slot)
+		log.Debug("Refreshed the blockhash", "blockhash", recent.Value.Blockhash)
+	}
+}
+
+// WatchExpiry stops the run once all the transactions are sent and the latest blockhash expired,
+// past that point none of the pending transactions can land. The block height is only checked
+// once the slot subscription reached the earliest slot the blockhash can expire at, when the slots
+// can't be followed, the run is stopped at the estimated stop time instead
+func (b *Benchmark) WatchExpiry(rpcClient *rpc.Client) {
+	if b.Slots == nil {
+		time.AfterFunc(time.Until(b.StopTime), b.Stop)
+		return
+	}
+
+	ticker := time.NewTicker(ExpiryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// the blockhash is still refreshed while sending
+		b.mu.RLock()
+		done := b.SendingDone
+		b.mu.RUnlock()
+
+		if !done {
+			continue
+		}
+
+		// the block height can't pass the last valid one before this slot, since some slots may be skipped
+		lastValidBlockHeight, slot := b.BlockhashExpiry()
+		if b.Slots.Available() && b.Slots.Slot() < slot+BlockhashValidBlocks+BlockhashExpiryMargin {
+			continue
+		}
+
+		// the height is read at the listener commitment, so that the landings
+		// of the last valid block had the time to reach the commitment
+		height, err := rpcClient.GetBlockHeight(b.ctx, GlobalConfig.GetCommitment())
+		if err != nil {
+			log.Warn("Unable to get the block height", "err", err)
+			continue
+		}
+
+		if height > lastValidBlockHeight+BlockhashExpiryMargin {
+			log.Info("Blockhash expired, stopping the run", "block_height", height, "last_valid_block_height", lastValidBlockHeight)
+			b.Stop()
+			return
+		}
+	}
+}
+
+// AverageTxSize returns the average serialized size of the sent transactions in bytes
+func (b *Benchmark) AverageTxSize() float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	count := len(b.TxRecords)
+	total := 0
+	for _, record := range b.TxRecords {
+		total += record.Size
+	}
+
+	if b.Streamed != nil {
+		count += int(b.Streamed.Count)
+		total += b.Streamed.TotalSize
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return float64(total) / float64(count)
+}
+
+// AchievedLandRate returns the number of transactions landed per second,
+// from the start of the send window until the last landing
+func (b *Benchmark) AchievedLandRate() float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	window := b.LastLandTime.Sub(b.SpamStartTime).Seconds()
+	if b.ProcessedTransactions == 0 || window <= 0 {
+		return 0
+	}
+
+	return float64(b.ProcessedTransactions) / window
+}
+
+// LandingWindow returns the time from the start of the send window until the first and the last landings
+// with a known landing time, false if there's none or the start is unknown, e.g. in listen only mode
+func (b *Benchmark) LandingWindow() (time.Duration, time.Duration, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.SpamStartTime.IsZero() {
+		return 0, 0, false
+	}
+
+	var first, last time.Time
+	if b.Streamed != nil {
+		first, last = b.Streamed.FirstLandTime, b.Streamed.LastLandTime
+	}
+
+	for _, record := range b.TxRecords {
+		if !record.HasLandingTime() {
+			continue
+		}
+
+		if first.IsZero() || record.LandTime.Before(first) {
+			first = record.LandTime
+		}
+		if record.LandTime.After(last) {
+			last = record.LandTime
+		}
+	}
+
+	if first.IsZero() {
+		return 0, 0, false
+	}
+
+	return first.Sub(b.SpamStartTime), last.Sub(b.SpamStartTime), true
+}
+
+// AllTransactionsLanded reports whether every sent transaction landed,
+// it's always false while transactions are still being sent
+func (b *Benchmark) AllTransactionsLanded() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// the resent transactions can't land anymore, their resend replaces them
+	return b.SendingDone && b.resendsInFlight == 0 && b.ProcessedTransactions+b.ResentTransactions >= b.SentTransactions
+}
+
+// AchievedSendRate returns the number of transactions sent per second during the send window
+func (b *Benchmark) AchievedSendRate() float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	window := b.LastSendTime.Sub(b.SpamStartTime).Seconds()
+	if b.SentTransactions == 0 || window <= 0 {
+		return 0
+	}
+
+	return float64(b.SentTransactions) / window
+}
+
+// RecordLanding records the landing of a transaction in the given slot, as of now,
+// it returns the landing delta and false if the transaction wasn't sent by this run
+// or was already recorded
+func (b *Benchmark) RecordLanding(sig solana.Signature, slot uint64) (time.Duration, bool) {
+	return b.RecordLandingAt(sig, slot, time.Now())
+}
+
+// RecordLandingAt records the landing of a transaction in the given slot at the given time,
+// the send and landing times must both be read with time.Now so that the delta uses the monotonic clock
+func (b *Benchmark) RecordLandingAt(sig solana.Signature, slot uint64, landTime time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	record, ok := b.claimLanding(sig)
+	if !ok {
+		return 0, false
+	}
+
+	b.ProcessedTransactions += 1
+
+	// a landing before the send can't be measured, it's flagged and kept out of the stats
+	delta := landTime.Sub(record.SendTime)
+	negative := delta < 0
+	if negative {
+		log.Warn("Tx landing time before its send time, excluded from the landing times", "sig", sig.String(), "delta", delta)
+
+		b.NegativeDeltas += 1
+		delta = 0
+	} else {
+		b.recordDelta(delta)
+	}
+
+	// record the block where the tx landed
+	// add new entry if needed
+	if _, ok := b.TxBlocks[slot]; !ok {
+		b.TxBlocks[slot] = 0
+	}
+
+	// increment the tx count for this block
+	b.TxBlocks[slot] += 1
+
+	record.Landed = true
+	record.NegativeDelta = negative
+	record.LandTime = landTime
+	b.LastLandTime = record.LandTime
+	record.Delta = delta
+	record.Slot = slot
+
+	b.recordSlotDistance(record)
+	b.WriteLandedEvent(record)
+	b.endTxSpan(record)
+	b.streamRecord(record)
+
+	LandedCounter.WithLabelValues(b.TestID, b.Endpoint.GetLabel()).Inc()
+	if !negative {
+		LandingHistogram.WithLabelValues(b.TestID, b.Endpoint.GetLabel()).Observe(delta.Seconds())
+	}
+
+	return delta, true
+}
+
+// recordSlotDistance records the number of slots it took the transaction to land,
+// from its send slot and from its blockhash slot, it must be called with the lock held
+func (b *Benchmark) recordSlotDistance(record *TxRecord) {
+	if record.SendSlot > 0 && record.Slot >= record.SendSlot {
+		if b.SlotDistanceSketch != nil {
+			b.SlotDistanceSketch.Add(float64(record.Slot - record.SendSlot))
+		} else {
+			b.TxSlotDistances = append(b.TxSlotDistances, record.Slot-record.SendSlot)
+		}
+	}
+
+	if record.BlockhashSlot > 0 && record.Slot >= record.BlockhashSlot {
+		if b.InclusionOffsetSketch != nil {
+			b.InclusionOffsetSketch.Add(float64(record.Slot - record.BlockhashSlot))
+		} else {
+			b.TxInclusionOffsets = append(b.TxInclusionOffsets, record.Slot-record.BlockhashSlot)
+		}
+	}
+}
+
+// claimLanding returns the record of a transaction about to be counted as landed, false if it wasn't sent
+// during this run, e.g. a stale landing of a previous test, or if it was already counted, e.g. a duplicate
+// notification, so that the landed count never exceeds the sent count, it must be called with the lock held
+func (b *Benchmark) claimLanding(sig solana.Signature) (*TxRecord, bool) {
+	record, ok := b.TxRecords[sig]
+	if !ok || record.Landed {
+		return nil, false
+	}
+
+	// can't happen unless a landing was counted twice
+	if b.ProcessedTransactions >= b.SentTransactions {
+		log.Warn("Tx landing ignored, all the sent transactions already landed", "sig", sig.String())
+		return nil, false
+	}
+
+	return record, true
+}
+
+// RecordBackfilledLanding records a transaction found landed by a status sweep,
+// since the exact landing time is unknown, no delta is recorded for it
+func (b *Benchmark) RecordBackfilledLanding(sig solana.Signature, slot uint64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	record, ok := b.claimLanding(sig)
+	if !ok {
+		return false
+	}
+
+	b.ProcessedTransactions += 1
+
+	if _, ok := b.TxBlocks[slot]; !ok {
+		b.TxBlocks[slot] = 0
+	}
+	b.TxBlocks[slot] += 1
+
+	record.Landed = true
+	record.Backfilled = true
+	record.Slot = slot
+
+	// unlike the landing time, the landing slot is known
+	b.recordSlotDistance(record)
+	b.WriteLandedEvent(record)
+	b.endTxSpan(record)
+	b.streamRecord(record)
+
+	// the landing time is unknown, so it's not observed in the histogram
+	LandedCounter.WithLabelValues(b.TestID, b.Endpoint.GetLabel()).Inc()
+
+	return true
+}
+
+// CommitmentReached reports whether a transaction with the given status reached the commitment level
+func CommitmentReached(status rpc.ConfirmationStatusType, commitment rpc.CommitmentType) bool {
+	switch commitment {
+	case rpc.CommitmentFinalized:
+		return status == rpc.ConfirmationStatusFinalized
+	case rpc.CommitmentConfirmed:
+		return status == rpc.ConfirmationStatusConfirmed || status == rpc.ConfirmationStatusFinalized
+	default:
+		return true
+	}
+}
+
+// SweepStatuses fetches the statuses of the transactions that haven't landed yet,
+// and passes the ones that reached the commitment level to record,
+// it returns the number of landings recorded
+func (b *Benchmark) SweepStatuses(ctx context.Context, rpcClient *rpc.Client, record func(sig solana.Signature, slot uint64) bool) int {
+	pending := b.UnlandedSignatures()
+
+	recorded := 0
+	for start := 0; start < len(pending); start += MaxSignatureStatuses {
+		batch := pending[start:min(start+MaxSignatureStatuses, len(pending))]
+
+		out, err := rpcClient.GetSignatureStatuses(ctx, false, batch...)
+		if err != nil {
+			log.Errorf("error getting signature statuses: %v", err)
+			continue
+		}
+
+		for i, status := range out.Value {
+			if status == nil || status.Err != nil || !CommitmentReached(status.ConfirmationStatus, GlobalConfig.GetCommitment()) {
+				continue
+			}
+
+			if record(batch[i], status.Slot) {
+				recorded++
+			}
+		}
+	}
+
+	return recorded
+}
+
+// UnlandedSignatures returns the signatures of the sent transactions that haven't been seen landed yet
+func (b *Benchmark) UnlandedSignatures() []solana.Signature {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	out := []solana.Signature{}
+	for sig, tx := range b.TxRecords {
+		if !tx.Landed {
+			out = append(out, sig)
+		}
+	}
+
+	return out
+}
+
+// BackfillLandings sweeps the statuses of the transactions that haven't landed yet,
+// to recover the landings missed while the websocket was disconnected
+func (b *Benchmark) BackfillLandings() {
+	backfilled := b.SweepStatuses(b.ctx, NewRpcClient(b.Endpoint.RpcUrl), b.RecordBackfilledLanding)
+
+	if backfilled > 0 {
+		log.Info("Backfilled transactions that landed while disconnected", "count", backfilled, "landed", fmt.Sprintf("%d/%d", b.ProcessedTransactions, b.SentTransactions))
+	}
+}
+
+func (b *Benchmark) DisplayBlocks() {
+	// find the first & last blocks
+	// and the block with the most transactions
+	var first uint64 = math.MaxUint64
+	var last uint64
+	var top uint64
+
+	for block, count := range b.TxBlocks {
+		first = uint64(math.Min(float64(first), float64(block)))
+		last = uint64(math.Max(float64(last), float64(block)))
+		top = uint64(math.Max(float64(top), float64(count)))
+	}
+
+	// the median landing time of each block, to see whether the later slots were slower
+	var latencies map[uint64]time.Duration
+	if GlobalConfig.BlockLatencies {
+		latencies = b.BlockLatencies()
+	}
+
+	for block := first; block <= last; block++ {
+		count, ok := b.TxBlocks[block]
+		if !ok {
+			// the leader of an empty block is still shown, to spot the leaders skipping the transactions
+			leader := strings.TrimSuffix(b.SlotLeaderLabel(block), " | ")
+			if leader != "" {
+				leader = " |        | " + leader
+			}
+
+			SimpleLogger.Printf("Block %s : %3d%s", message.NewPrinter(language.English).Sprintf("%d", block), count, leader)
+			continue
+		}
+
+		// deduce the # of * characters to display
+		// use math.Ceil to round up to ensure we don't display 0 * characters
+		// (only for blocks with > 0 transactions)
+		stars := math.Ceil(float64(count) / float64(b.ProcessedTransactions) * 100)
+
+		latency, rank := "", ""
+		if GlobalConfig.BlockLatencies {
+			latency, rank = BlockLatencyLabel(latencies, block), BlockLatencyRank(latencies, block)
+		}
+
+		SimpleLogger.Printf("Block %s : %3d | %5.1f%% | %s%s%s%s%s",
+			message.NewPrinter(language.English).Sprintf("%d", block),
+			count,
+			float64(count)/float64(b.ProcessedTransactions)*100,
+			b.SlotLeaderLabel(block),
+			b.BlockStatusLabel(block),
+			latency,
+			strings.Repeat("*", int(stars)),
+			rank,
+		)
+	}
+}
+
+// DisplayLatencyHistogram logs the number of transactions landed in each landing time bucket
+func (b *Benchmark) DisplayLatencyHistogram() {
+	width := GlobalConfig.GetHistogramBucket()
+
+	// count the transactions of each bucket, the outliers are grouped in the last bucket
+	counts := b.latencyCounts()
+	first, last := MaxHistogramBuckets-1, 0
+	var total float64
+	for bucket, count := range counts {
+		if count == 0 {
+			continue
+		}
+
+		first = min(first, bucket)
+		last = max(last, bucket)
+		total += float64(count)
+	}
+
+	SimpleLogger.Printf("")
+	for bucket := first; bucket <= last; bucket++ {
+		from := time.Duration(bucket) * width
+
+		label := fmt.Sprintf("%s - %s", from, from+width)
+		if bucket == MaxHistogramBuckets-1 {
+			label = fmt.Sprintf(">= %s", from)
+		}
+
+		// use math.Ceil to round up to ensure we don't display 0 * characters
+		// (only for buckets with > 0 transactions)
+		stars := math.Ceil(float64(counts[bucket]) / total * 100)
+
+		SimpleLogger.Printf("Latency %-17s : %3d | %5.1f%% | %s",
+			label,
+			counts[bucket],
+			float64(counts[bucket])/total*100,
+			strings.Repeat("*", int(stars)),
+		)
+	}
+}
+
+// Summary returns the summary lines of the run, the charts and breakdowns are logged separately
+func (b *Benchmark) Summary() *Summary {
+	s := &Summary{}
+	s.Section()
+	s.Add("Finished Test ID", "%s", b.TestID)
+	if GlobalConfig.GetRepeat() > 1 {
+		s.Add("Run", "%d/%d", b.RunNumber, GlobalConfig.GetRepeat())
+	}
+	if GlobalConfig.DryRun {
+		s.Add("Dry Run", "%d transactions built and signed, none sent", b.DryRunTransactions)
+	}
+	if len(GlobalConfig.GetEndpoints()) > 1 {
+		s.Add("Endpoint", "%s", b.Endpoint.GetLabel())
+	}
+	s.Add("RPC URL", "%s", b.Endpoint.RpcUrl)
+	s.Add("WS URL", "%s", b.Endpoint.GetWsUrl())
+	if GlobalConfig.GetSendMode() == SendModeJito {
+		s.Add("Jito Block Engine URL", "%s", GlobalConfig.JitoUrl)
+		s.Add("Jito Tip", "%d Lamports", GlobalConfig.JitoTip)
+	} else {
+		for _, sendUrl := range b.Endpoint.GetSendUrls() {
+			s.Add("RPC Send URL", "%s", sendUrl)
+		}
+	}
+	if GlobalConfig.Duration > 0 {
+		s.Add("Test Duration", "%s", time.Duration(GlobalConfig.Duration)*time.Second)
+	} else {
+		s.Add("Transaction Count", "%d", GlobalConfig.TxCount)
+	}
+	if GlobalConfig.IsRamp() {
+		s.Add("Rate Ramp", "%d to %d tx/s in %d steps", GlobalConfig.RateStart, GlobalConfig.RateEnd, GlobalConfig.GetRateSteps())
+	} else {
+		s.Add("Rate Limit", "%d", GlobalConfig.RateLimit)
+	}
+	if GlobalConfig.GetBurst() != GlobalConfig.GetRateLimit() {
+		s.Add("Burst", "%d", GlobalConfig.GetBurst())
+	}
+	if GlobalConfig.SendWindow > 0 {
+		s.Add("Send Window", "%s", GlobalConfig.GetSendWindow())
+	}
+	if GlobalConfig.IsFeeSweep() {
+		s.Add("Compute Unit Price", "%d to %d micro-lamports in %d steps", GlobalConfig.FeeMin, GlobalConfig.FeeMax, GlobalConfig.GetFeeSteps())
+	} else {
+		s.Add("Compute Unit Price", "%s", FormatComputeUnitPrice(b.ComputeUnitPrice))
+	}
+	s.Add("Compute Unit Limit", "%d", GlobalConfig.GetComputeUnitLimit())
+	s.Add("Commitment", "%s", GlobalConfig.GetCommitment())
+	s.Add("Confirm Mode", "%s", GlobalConfig.GetConfirmMode())
+	if GlobalConfig.GetConfirmSource() != ConfirmSourceWs {
+		s.Add("Confirm Source", "%s", GlobalConfig.GetConfirmSource())
+	}
+	s.Add("Workload", "%s", GlobalConfig.GetWorkload())
+	if GlobalConfig.SplitMode != "" {
+		s.Add("Split Mode", "%s", GlobalConfig.SplitMode)
+	}
+	switch {
+	case GlobalConfig.DryRun:
+		// the sizes of the built transactions are logged instead
+	case GlobalConfig.SplitMode == SplitModeListenOnly:
+		// the transactions are built by the send only side
+	case GlobalConfig.TxSizeBytes > 0:
+		s.Add("Avg Tx Size", "%.0f bytes (target %d bytes)", b.AverageTxSize(), GlobalConfig.TxSizeBytes)
+	default:
+		s.Add("Avg Tx Size", "%.0f bytes", b.AverageTxSize())
+	}
+	s.Add("Tx Version", "%s", GlobalConfig.GetTxVersion())
+	s.Add("Node Retries", "%d", GlobalConfig.NodeRetries)
+	if GlobalConfig.WarmupTxCount > 0 {
+		s.Add("Warmup Transactions", "%d/%d (not measured)", b.WarmupTransactions, GlobalConfig.WarmupTxCount)
+	}
+	s.Add("Sent TPS", "%.1f tx/s", b.AchievedSendRate())
+	s.Add("Landed TPS", "%.1f tx/s", b.AchievedLandRate())
+	if batches, txs := b.SendBatches(); batches > 0 {
+		s.Add("Send Batches", "%d (avg %.1f txs)", batches, float64(txs)/float64(batches))
+	}
+	if b.RateLimitedTransactions > 0 {
+		s.Add("Rate Limited Sends", "%d (backed off and retried)", b.RateLimitedTransactions)
+	}
+	if b.Throttle.Count > 0 {
+		s.Add("Throttle Delay", "%s", b.ThrottleSummary())
+	}
+	if b.TimedOutTransactions > 0 {
+		s.Add("Send Timeouts", "%d (not sent)", b.TimedOutTransactions)
+	}
+	if b.PreflightFailedTransactions > 0 {
+		s.Add("Preflight Failures", "%d (not sent)", b.PreflightFailedTransactions)
+	}
+	if b.NegativeDeltas > 0 {
+		s.Add("Negative Deltas", "%d (landed before their send time, excluded from the landing times)", b.NegativeDeltas)
+	}
+	if b.AbortedEarly {
+		s.Add("Aborted Early", "landing rate below %.1f%% after %d txs, partial results", GlobalConfig.EarlyAbortThreshold*100, GlobalConfig.EarlyAbortWindow)
+	}
+	switch {
+	case GlobalConfig.DryRun:
+	case GlobalConfig.SplitMode == SplitModeSendOnly:
+		s.Add("Transactions Landed", "not tracked in send only mode, see the listen only side")
+	case GlobalConfig.SplitMode == SplitModeListenOnly:
+		s.Add("Transactions Landed", "%d (the sent count and send times are on the send only side)", b.ProcessedTransactions)
+	default:
+		s.Add("Transactions Landed", "%d/%d (%.1f%%)", b.ProcessedTransactions, b.SentTransactions, float64(b.ProcessedTransactions)/float64(b.SentTransactions)*100.0)
+	}
+	if b.Control != nil {
+		s.Add("Control Landed", "%d/%d (%.1f%%) at %s", b.Control.Landed, b.Control.Sent, b.Control.LandingRate()*100.0, FormatComputeUnitPrice(GlobalConfig.ControlCuPrice))
+		s.Add("Workload vs Control", "%s", b.ControlComparison())
+	}
+	if b.LandingsVerified > 0 {
+		s.Add("Reorged Landings", "%d/%d finalized landings (%d landed again in another slot)", b.ReorgedLandings, b.LandingsVerified, b.ReincludedLandings)
+	}
+	if first, last, ok := b.LandingWindow(); ok {
+		s.Add("Time To First Landing", "%s", first.Truncate(time.Millisecond))
+		s.Add("Time To Last Landing", "%s", last.Truncate(time.Millisecond))
+	}
+	if GlobalConfig.MaxResends > 0 {
+		sent, landed, avgAttempts := b.ResendStats()
+		s.Add("Resent Transactions", "%d (expired before landing, max %d resends)", b.ResentTransactions, GlobalConfig.MaxResends)
+		if sent > 0 {
+			s.Add("Landed With Resends", "%d/%d (%.1f%%)", landed, sent, float64(landed)/float64(sent)*100.0)
+		}
+		if landed > 0 {
+			s.Add("Avg Attempts to Land", "%.2f", avgAttempts)
+		}
+	}
+
+	// calculate landing time results, if there was any
+	if landing, ok := b.LandingTimeStats(); ok {
+		s.Add("Min Tx Landing Time", "%s", landing.Min.Truncate(time.Millisecond))
+		s.Add("Max Tx Landing Time", "%s", landing.Max.Truncate(time.Millisecond))
+		s.Add("Avg Tx Landing Time", "%s", landing.Avg.Truncate(time.Millisecond))
+		s.Add("Median Tx Landing Time", "%s", landing.Median.Truncate(time.Millisecond))
+		for i, value := range b.LandingTimePercentiles() {
+			s.Add(FormatPercentile(GlobalConfig.GetPercentiles()[i])+" Tx Landing Time", "%s", value.Truncate(time.Millisecond))
+		}
+		s.Add("Landing Time Std Dev", "%s", landing.StdDev.Truncate(time.Millisecond))
+		s.Add("Landing Time IQR", "%s", landing.IQR.Truncate(time.Millisecond))
+		s.Section()
+	}
+
+	// calculate the slot landing distances, if there was any
+	if distance, ok := b.SlotDistanceStats(); ok {
+		s.Add("Min Slot Distance", "%.0f", distance.Min)
+		s.Add("Max Slot Distance", "%.0f", distance.Max)
+		s.Add("Avg Slot Distance", "%.2f", distance.Avg)
+		s.Add("Median Slot Distance", "%.1f", distance.Median)
+		s.Add("P90 Slot Distance", "%.1f", distance.P90)
+		s.Add("P95 Slot Distance", "%.1f", distance.P95)
+		s.Add("P99 Slot Distance", "%.1f", distance.P99)
+		s.Section()
+	}
+
+	// the inclusion delay, independent of the round trip to the rpc
+	if offset, ok := b.InclusionOffsetStats(); ok {
+		s.Add("Inclusion Slot Offset", "min %.0f, avg %.2f, median %.1f, p90 %.1f, p95 %.1f, p99 %.1f, max %.0f", offset.Min, offset.Avg, offset.Median, offset.P90, offset.P95, offset.P99, offset.Max)
+		s.Section()
+	}
+
+	// the time the RPC took to accept the transactions, independent of their landing
+	if latency, ok := b.SendLatencyStats(); ok {
+		s.Add("Send Latency", "min %s, avg %s, median %s, p90 %s, p95 %s, p99 %s, max %s",
+			latency.Min.Truncate(time.Millisecond),
+			latency.Avg.Truncate(time.Millisecond),
+			latency.Median.Truncate(time.Millisecond),
+			latency.P90.Truncate(time.Millisecond),
+			latency.P95.Truncate(time.Millisecond),
+			latency.P99.Truncate(time.Millisecond),
+			latency.Max.Truncate(time.Millisecond),
+		)
+		s.Section()
+	}
+
+	return s
+}
+
+// PrintSummary logs the results of the run
+func (b *Benchmark) PrintSummary() {
+	b.Summary().Display()
+
+	if GlobalConfig.TrackAllCommitments {
+		b.DisplayLifecycle()
+	}
+
+	if b.CrossCheck != nil {
+		b.DisplayCrossCheck()
+	}
+
+	if len(b.SendErrors) > 0 {
+		b.DisplayErrorBreakdown()
+	}
+
+	if dropped, txs := b.DroppedBlocks(); dropped > 0 {
+		SimpleLogger.Printf("Dropped Blocks         : %d (%d txs rolled back)", dropped, txs)
+		SimpleLogger.Printf("")
+	}
+
+	if len(b.TxBlocks) > 0 {
+		b.DisplayBlocks()
+	}
+
+	if len(b.SlotLeaders) > 0 {
+		b.DisplayLeaders()
+	}
+
+	if _, ok := b.LandingTimeStats(); ok {
+		b.DisplayLatencyHistogram()
+	}
+
+	if len(TestAccounts) > 1 {
+		DisplayGroups("Wallet", b.WalletStats())
+	}
+
+	if GlobalConfig.GetSendMode() == SendModeRpc && len(b.Endpoint.GetSendUrls()) > 1 {
+		DisplayGroups("Send URL", b.SendUrlStats())
+	}
+
+	if GlobalConfig.IsRamp() {
+		DisplayGroups("Send Rate (tx/s)", b.RateBandStats())
+	}
+
+	if GlobalConfig.IsFeeSweep() {
+		DisplayGroups("CU Price (micro-lamports)", b.FeeLevelStats())
+	}
+
+	b.DisplayDropped()
+}
+
+// GroupStats holds the results of a group of transactions, e.g. the ones paid by a single test wallet
+type GroupStats struct {
+	Key    string
+	Sent   uint64
+	Landed uint64
+
+	// landing times of the transactions that weren't backfilled
+	Deltas []time.Duration
+
+	// with streaming_stats, the landing times are summarized in the sketch instead
+	Sketch *Sketch
+}
+
+// addDelta records the landing time of a transaction of the group
+func (g *GroupStats) addDelta(delta time.Duration) {
+	if g.Sketch != nil {
+		g.Sketch.Add(float64(delta))
+		return
+	}
+
+	g.Deltas = append(g.Deltas, delta)
+}
+
+// LandingStats returns the landing time stats of the group, false if no landing time was recorded
+func (g *GroupStats) LandingStats() (LandingStats, bool) {
+	if g.Sketch != nil {
+		return g.Sketch.LandingStats(), g.Sketch.Count() > 0
+	}
+
+	if len(g.Deltas) == 0 {
+		return LandingStats{}, false
+	}
+
+	return ComputeLandingStats(g.Deltas), true
+}
+
+// the groups of transactions of the summary
+const (
+	GroupWallet   = "wallet"
+	GroupSendUrl  = "send_url"
+	GroupRampRate = "ramp_rate"
+	GroupFeeLevel = "fee_level"
+)
+
+// the key of the record in each group of transactions
+var recordGroupKeys = map[string]func(record *TxRecord) string{
+	GroupWallet:   func(record *TxRecord) string { return record.Wallet.String() },
+	GroupSendUrl:  func(record *TxRecord) string { return record.SendUrl },
+	GroupRampRate: func(record *TxRecord) string { return strconv.FormatUint(record.RampRate, 10) },
+	GroupFeeLevel: func(record *TxRecord) string { return strconv.FormatUint(record.ComputeUnitPrice, 10) },
+}
+
+// GroupStats returns the results of the transactions of the group by key, in the order of the keys
+func (b *Benchmark) GroupStats(group string, keys []string) []*GroupStats {
+	keyOf := recordGroupKeys[group]
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	out := []*GroupStats{}
+	byKey := make(map[string]*GroupStats)
+	for _, key := range keys {
+		groupStats := &GroupStats{Key: key, Deltas: []time.Duration{}}
+
+		// start from the records already streamed out
+		if b.Streamed != nil {
+			groupStats.Sketch = NewSketch()
+			if streamed, ok := b.Streamed.Groups[group][key]; ok {
+				groupStats.Sent = streamed.Sent
+				groupStats.Landed = streamed.Landed
+				groupStats.Sketch.Merge(streamed.Sketch)
+			}
+		}
+
+		byKey[key] = groupStats
+		out = append(out, groupStats)
+	}
+
+	for _, record := range b.TxRecords {
+		groupStats, ok := byKey[keyOf(record)]
+		if !ok {
+			continue
+		}
+
+		groupStats.Sent += 1
+		if record.Landed {
+			groupStats.Landed += 1
+		}
+		if record.HasLandingTime() {
+			groupStats.addDelta(record.Delta)
+		}
+	}
+
+	return out
+}
+
+// WalletStats returns the results of each test wallet, in the order of the test wallets
+func (b *Benchmark) WalletStats() []*GroupStats {
+	keys := []string{}
+	for _, wallet := range TestAccounts {
+		keys = append(keys, wallet.PublicKey().String())
+	}
+
+	return b.GroupStats(GroupWallet, keys)
+}
+
+// SendUrlStats returns the results of each send url, in the order of the send urls
+func (b *Benchmark) SendUrlStats() []*GroupStats {
+	return b.GroupStats(GroupSendUrl, b.Endpoint.GetSendUrls())
+}
+
+// DisplayGroups logs the landing rate and landing times of each group
+func DisplayGroups(title string, groups []*GroupStats) {
+	width := len(title)
+	for _, groupStats := range groups {
+		width = max(width, len(groupStats.Key))
+	}
+
+	SimpleLogger.Printf("")
+	SimpleLogger.Printf("%-*s | %-17s | %9s | %9s", width, title, "Landed", "Median", "P90")
+
+	for _, groupStats := range groups {
+		landed := fmt.Sprintf("%d/%d (%.1f%%)", groupStats.Landed, groupStats.Sent, float64(groupStats.Landed)/float64(groupStats.Sent)*100.0)
+
+		landing, ok := groupStats.LandingStats()
+		if !ok {
+			SimpleLogger.Printf("%-*s | %-17s | %9s | %9s", width, groupStats.Key, landed, "-", "-")
+			continue
+		}
+
+		SimpleLogger.Printf("%-*s | %-17s | %9s | %9s", width, groupStats.Key, landed, landing.Median.Truncate(time.Millisecond), landing.P90.Truncate(time.Millisecond))
+	}
+}
+
+// DroppedTransactions returns the records of the sent transactions that never landed, in send order
+func (b *Benchmark) DroppedTransactions() []*TxRecord {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	dropped := []*TxRecord{}
+
+	// the landings aren't tracked in send only mode
+	if GlobalConfig.SplitMode == SplitModeSendOnly {
+		return dropped
+	}
+
+	for _, record := range b.TxRecords {
+		if !record.Landed {
+			dropped = append(dropped, record)
+		}
+	}
+
+	sort.Slice(dropped, func(i, j int) bool {
+		return dropped[i].Num < dropped[j].Num
+	})
+
+	return dropped
+}
+
+func (b *Benchmark) DisplayDropped() {
+	dropped := b.DroppedTransactions()
+	if len(dropped) == 0 {
+		return
+	}
+
+	SimpleLogger.Printf("")
+	SimpleLogger.Printf("Dropped Transactions   : %d", len(dropped))
+
+	for i, record := range dropped {
+		// avoid flooding the console, the full list is in the csv file
+		if i >= MaxDisplayedDropped {
+			SimpleLogger.Printf("  ... and %d more, see %s", len(dropped)-i, TxRecordsFileName)
+			break
+		}
+
+		SimpleLogger.Printf("  #%-6d %s", record.Num, record.Signature)
+	}
+}
@@ -0,0 +1,172 @@
+package bench
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+)
+
+// newTestBenchmark creates a run against a local endpoint, with the given number of transactions
+// sent at the given time, none of them landed yet
+func newTestBenchmark(t *testing.T, count int, sendTime time.Time) (*Benchmark, []solana.Signature) {
+	t.Helper()
+
+	// the warnings of the landings aren't part of the test output
+	logger := log.Default()
+	log.SetDefault(log.New(io.Discard))
+	t.Cleanup(func() { log.SetDefault(logger) })
+
+	GlobalConfig = &Config{RpcUrl: "http://localhost:8899", TxCount: uint64(count), RateLimit: 10}
+	b := NewBenchmark(context.Background(), GlobalConfig.GetEndpoints()[0], "abcd1234", 1)
+
+	sigs := []solana.Signature{}
+	for i := 1; i <= count; i++ {
+		sig := solana.SignatureFromBytes(append(make([]byte, 63), byte(i)))
+		b.TxRecords[sig] = &TxRecord{Signature: sig, Num: uint64(i), SendTime: sendTime, Attempt: 1}
+		b.SentTransactions += 1
+		sigs = append(sigs, sig)
+	}
+
+	return b, sigs
+}
+
+func TestRecordLandingAtNegativeDelta(t *testing.T) {
+	sendTime := time.Now()
+	b, sigs := newTestBenchmark(t, 1, sendTime)
+
+	delta, ok := b.RecordLandingAt(sigs[0], 100, sendTime.Add(-50*time.Millisecond))
+	if !ok {
+		t.Fatal("landing not recorded")
+	}
+
+	if delta != 0 {
+		t.Errorf("delta = %s, want 0", delta)
+	}
+
+	if b.NegativeDeltas != 1 {
+		t.Errorf("NegativeDeltas = %d, want 1", b.NegativeDeltas)
+	}
+
+	if b.ProcessedTransactions != 1 {
+		t.Errorf("ProcessedTransactions = %d, want 1", b.ProcessedTransactions)
+	}
+
+	record := b.TxRecords[sigs[0]]
+	if !record.Landed || record.Slot != 100 {
+		t.Errorf("record landed = %t in slot %d, want true in slot 100", record.Landed, record.Slot)
+	}
+	if !record.NegativeDelta {
+		t.Error("record not flagged with a negative delta")
+	}
+	if record.Delta != 0 {
+		t.Errorf("record delta = %s, want 0", record.Delta)
+	}
+	if record.HasLandingTime() {
+		t.Error("record with a negative delta has a landing time")
+	}
+
+	// the landing is counted, but kept out of the landing times
+	if len(b.TxDeltas) != 0 {
+		t.Errorf("TxDeltas = %v, want none", b.TxDeltas)
+	}
+	if _, ok := b.LandingTimeStats(); ok {
+		t.Error("landing time stats computed from a negative delta")
+	}
+}
+
+func TestDuplicateLandingCountedOnce(t *testing.T) {
+	sendTime := time.Now()
+	b, sigs := newTestBenchmark(t, 1, sendTime)
+
+	landTime := sendTime.Add(400 * time.Millisecond)
+	if _, ok := b.RecordLandingAt(sigs[0], 100, landTime); !ok {
+		t.Fatal("websocket landing not recorded")
+	}
+
+	// the same landing found again by the status sweep, in a later slot
+	if b.RecordBackfilledLanding(sigs[0], 101) {
+		t.Error("backfilled landing of an already landed transaction recorded")
+	}
+
+	// and notified again by the websocket
+	if b.HandleLanding(sigs[0], 102) {
+		t.Error("duplicate websocket landing recorded")
+	}
+
+	if b.ProcessedTransactions != 1 {
+		t.Errorf("ProcessedTransactions = %d, want 1", b.ProcessedTransactions)
+	}
+
+	record := b.TxRecords[sigs[0]]
+	if !record.LandTime.Equal(landTime) || record.Delta != 400*time.Millisecond || record.Slot != 100 {
+		t.Errorf("record landed at %s (delta %s) in slot %d, want the first landing at %s (delta 400ms) in slot 100", record.LandTime, record.Delta, record.Slot, landTime)
+	}
+	if record.Backfilled {
+		t.Error("record flagged as backfilled by the duplicate")
+	}
+
+	if len(b.TxDeltas) != 1 || b.TxBlocks[100] != 1 || b.TxBlocks[101] != 0 {
+		t.Errorf("TxDeltas = %v, TxBlocks = %v, want a single landing in slot 100", b.TxDeltas, b.TxBlocks)
+	}
+}
+
+func TestBackfilledLandingThenWebsocket(t *testing.T) {
+	b, sigs := newTestBenchmark(t, 1, time.Now())
+
+	if !b.RecordBackfilledLanding(sigs[0], 100) {
+		t.Fatal("backfilled landing not recorded")
+	}
+
+	if b.HandleLanding(sigs[0], 100) {
+		t.Error("websocket landing of an already backfilled transaction recorded")
+	}
+
+	if b.ProcessedTransactions != 1 {
+		t.Errorf("ProcessedTransactions = %d, want 1", b.ProcessedTransactions)
+	}
+
+	// the first landing wins, its time stays unknown
+	record := b.TxRecords[sigs[0]]
+	if !record.Backfilled || record.HasLandingTime() || len(b.TxDeltas) != 0 {
+		t.Errorf("record backfilled = %t with TxDeltas = %v, want the backfilled landing only", record.Backfilled, b.TxDeltas)
+	}
+}
+
+func TestStaleLandingIgnored(t *testing.T) {
+	// a transaction of a previous run, which sent one more than the current run
+	previous, sigs := newTestBenchmark(t, 2, time.Now())
+	stale := sigs[1]
+	if _, ok := previous.RecordLandingAt(stale, 100, time.Now()); !ok {
+		t.Fatal("landing of the previous run not recorded")
+	}
+
+	b, _ := newTestBenchmark(t, 1, time.Now())
+	unknown := solana.SignatureFromBytes(append(make([]byte, 63), 0xff))
+
+	for _, sig := range []solana.Signature{stale, unknown} {
+		if _, ok := b.RecordLandingAt(sig, 100, time.Now()); ok {
+			t.Errorf("landing of %s recorded", sig)
+		}
+		if b.HandleLanding(sig, 100) {
+			t.Errorf("websocket landing of %s recorded", sig)
+		}
+		if b.RecordBackfilledLanding(sig, 100) {
+			t.Errorf("backfilled landing of %s recorded", sig)
+		}
+	}
+
+	// the memo of another test is neither recorded by its number nor by its signature
+	re := MemoRegexp(GlobalConfig.GetMemoTemplate())
+	b.HandleLogs(re, unknown, 100, []string{`Program log: Memo (len 30): "memobench: Test 1 [deadbeef]"`})
+
+	if b.ProcessedTransactions != 0 || len(b.TxDeltas) != 0 || len(b.TxBlocks) != 0 {
+		t.Errorf("ProcessedTransactions = %d, TxDeltas = %v, TxBlocks = %v, want no landing", b.ProcessedTransactions, b.TxDeltas, b.TxBlocks)
+	}
+	if _, ok := b.TxRecords[unknown]; ok {
+		t.Error("record created for an unknown signature")
+	}
+}
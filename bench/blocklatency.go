@@ -0,0 +1,78 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/montanaflynn/stats"
+)
+
+// BlockLatencies returns the median landing time of the transactions of each block,
+// the blocks whose transactions have no known landing time are left out
+func (b *Benchmark) BlockLatencies() map[uint64]time.Duration {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	deltas := make(map[uint64][]float64)
+	for _, record := range b.TxRecords {
+		if record.HasLandingTime() {
+			deltas[record.Slot] = append(deltas[record.Slot], float64(record.Delta))
+		}
+	}
+
+	out := make(map[uint64]time.Duration, len(deltas))
+	for slot, blockDeltas := range deltas {
+		median, _ := stats.Median(blockDeltas)
+		out[slot] = time.Duration(median)
+	}
+
+	// the landing times of the records streamed out are in the sketches of their blocks
+	if b.Streamed != nil {
+		for slot, sketch := range b.Streamed.BlockDeltas {
+			merged := NewSketch()
+			merged.Merge(sketch)
+			for _, delta := range deltas[slot] {
+				merged.Add(delta)
+			}
+
+			out[slot] = time.Duration(merged.Quantile(0.5))
+		}
+	}
+
+	return out
+}
+
+// BlockLatencyLabel returns the median landing time of the block for the block chart
+func BlockLatencyLabel(latencies map[uint64]time.Duration, slot uint64) string {
+	latency, ok := latencies[slot]
+	if !ok {
+		return fmt.Sprintf("%8s | ", "-")
+	}
+
+	return fmt.Sprintf("%8s | ", latency.Truncate(time.Millisecond))
+}
+
+// BlockLatencyRank flags the blocks with the fastest and slowest median landing time of the run
+func BlockLatencyRank(latencies map[uint64]time.Duration, slot uint64) string {
+	latency, ok := latencies[slot]
+
+	// a single block is both, there's nothing to compare
+	if !ok || len(latencies) < 2 {
+		return ""
+	}
+
+	fastest, slowest := true, true
+	for _, other := range latencies {
+		fastest = fastest && latency <= other
+		slowest = slowest && latency >= other
+	}
+
+	switch {
+	case fastest:
+		return " (fastest)"
+	case slowest:
+		return " (slowest)"
+	}
+
+	return ""
+}
@@ -0,0 +1,208 @@
+package bench
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// a block notification, or the error of the subscription it was received on
+type blockEvent struct {
+	got *ws.BlockResult
+	err error
+}
+
+// BlockListener streams the landings from block subscriptions filtered to the test wallets,
+// the transactions of each block are scanned for the test signatures, so unlike
+// the logs subscriptions, the notifications aren't affected by the log truncation
+type BlockListener struct {
+	Bench         *Benchmark
+	Client        *ws.Client
+	Subscriptions []*ws.BlockSubscription
+	Listening     bool
+
+	// the notifications of all the subscriptions of the current connection,
+	// and a channel closed when the connection is replaced
+	events chan blockEvent
+	closed chan struct{}
+
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func NewBlockListener(bench *Benchmark) *BlockListener {
+	return &BlockListener{
+		Bench:   bench,
+		stopped: make(chan struct{}),
+	}
+}
+
+// Connect connects to the websocket, the listener is then listening until stopped
+func (l *BlockListener) Connect() error {
+	if err := l.connect(); err != nil {
+		return err
+	}
+
+	l.Listening = true
+	return nil
+}
+
+func (l *BlockListener) IsListening() bool {
+	return l.Listening
+}
+
+// connect (re)connects to the websocket and subscribes to the blocks mentioning each test wallet,
+// since a block subscription can only mention a single account
+func (l *BlockListener) connect() error {
+	// close the previous connection if any
+	if l.Client != nil {
+		close(l.closed)
+		l.Client.Close()
+		l.Client = nil
+	}
+
+	wsClient, err := ConnectWs(l.Bench.ctx, l.Bench.Endpoint.GetWsUrl())
+	if err != nil {
+		return fmt.Errorf("error connecting to websocket: %w", err)
+	}
+
+	rewards := false
+	maxVersion := uint64(0)
+	opts := &ws.BlockSubscribeOpts{
+		Commitment:                     GlobalConfig.GetCommitment(),
+		Encoding:                       solana.EncodingBase64,
+		TransactionDetails:             rpc.TransactionDetailsFull,
+		Rewards:                        &rewards,
+		MaxSupportedTransactionVersion: &maxVersion,
+	}
+
+	subs := []*ws.BlockSubscription{}
+	for _, wallet := range TestAccounts {
+		sub, err := wsClient.BlockSubscribe(ws.NewBlockSubscribeFilterMentionsAccountOrProgram(wallet.PublicKey()), opts)
+		if err != nil {
+			wsClient.Close()
+			return fmt.Errorf("error subscribing to blocks: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	l.Client = wsClient
+	l.Subscriptions = subs
+	l.events = make(chan blockEvent)
+	l.closed = make(chan struct{})
+
+	for _, sub := range subs {
+		go l.forward(sub, l.events, l.closed)
+	}
+
+	return nil
+}
+
+// forward passes the notifications of the subscription to the events channel,
+// until the subscription fails, the connection is replaced or the listener is stopped
+func (l *BlockListener) forward(sub *ws.BlockSubscription, events chan<- blockEvent, closed <-chan struct{}) {
+	for {
+		got, err := sub.Recv()
+
+		select {
+		case events <- blockEvent{got: got, err: err}:
+		case <-closed:
+			return
+		case <-l.stopped:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Recv returns the next notification of any of the subscriptions
+func (l *BlockListener) Recv() (*ws.BlockResult, error) {
+	select {
+	case event := <-l.events:
+		return event.got, event.err
+	case <-l.stopped:
+		return nil, errListenerStopped
+	}
+}
+
+// Reconnect tries to restore a broken websocket connection
+func (l *BlockListener) Reconnect() bool {
+	return l.Bench.Reconnect("websocket", l.IsListening, l.connect)
+}
+
+// Start listens for the landed transactions until stopped, the listener must be connected beforehand
+func (l *BlockListener) Start() {
+	defer l.Bench.wg.Done()
+
+	log.Info("Listening for blocks...", "endpoint", l.Bench.Endpoint.GetLabel())
+
+	for l.Listening {
+		got, err := l.Recv()
+		if err != nil {
+			// the subscription errors out when the connection is lost
+			if !l.Listening {
+				break
+			}
+
+			log.Error("Websocket connection lost", "err", err)
+
+			if !l.Reconnect() {
+				// in both mode, the poller keeps tracking the landings
+				log.Error("Unable to reconnect to websocket, giving up")
+				l.Stop()
+				break
+			}
+
+			if l.Bench.AllTransactionsLanded() {
+				l.Bench.Stop()
+			}
+			continue
+		}
+
+		// the block may be missing if the node couldn't load it
+		if got == nil || got.Value.Block == nil {
+			if got != nil && got.Value.Err != nil {
+				log.Warn("Block notification without block", "slot", got.Value.Slot, "err", got.Value.Err)
+			}
+			continue
+		}
+
+		for _, txWithMeta := range got.Value.Block.Transactions {
+			// the failed transactions are included in the block, but didn't land
+			if txWithMeta.Meta == nil || txWithMeta.Meta.Err != nil {
+				continue
+			}
+
+			tx, err := txWithMeta.GetTransaction()
+			if err != nil || len(tx.Signatures) == 0 {
+				log.Warn("Unable to decode block transaction", "slot", got.Value.Slot, "err", err)
+				continue
+			}
+
+			// the signatures are matched directly, the other transactions of the wallets are ignored
+			l.Bench.HandleLanding(tx.Signatures[0], got.Value.Slot)
+		}
+	}
+
+	log.Info("Stopping listening for blocks...")
+}
+
+func (l *BlockListener) Stop() {
+	if !l.Listening {
+		return
+	}
+
+	l.Listening = false
+	l.stopOnce.Do(func() { close(l.stopped) })
+
+	for _, sub := range l.Subscriptions {
+		sub.Unsubscribe()
+	}
+}
@@ -0,0 +1,196 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// the commitment reached by a block the transactions landed in, checked after the run
+const (
+	BlockStatusProcessed = "processed"
+	BlockStatusConfirmed = "confirmed"
+	BlockStatusFinalized = "finalized"
+
+	// the block was skipped by the cluster, the transactions it contained were rolled back
+	BlockStatusDropped = "dropped"
+)
+
+// VerifyBlocks checks the commitment reached by each block the transactions landed in,
+// once the cluster finalized them or the finalization timeout elapsed
+func (b *Benchmark) VerifyBlocks(ctx context.Context) {
+	b.mu.RLock()
+	slots := []uint64{}
+	for slot := range b.TxBlocks {
+		slots = append(slots, slot)
+	}
+	b.mu.RUnlock()
+
+	if len(slots) == 0 {
+		return
+	}
+
+	first, last := slices.Min(slots), slices.Max(slots)
+	rpcClient := NewRpcClient(b.Endpoint.RpcUrl)
+
+	log.Info("Waiting for the landing blocks to be finalized...", "timeout", FinalizationTimeout)
+
+	// the blocks past the finalized slot may still be rolled back
+	var finalizedSlot uint64
+	for deadline := time.Now().Add(FinalizationTimeout); ; {
+		slot, err := rpcClient.GetSlot(ctx, rpc.CommitmentFinalized)
+		if err != nil {
+			log.Errorf("error getting finalized slot: %v", err)
+			return
+		}
+
+		finalizedSlot = slot
+		if finalizedSlot >= last || time.Now().After(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+
+	confirmedSlot, err := rpcClient.GetSlot(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		log.Errorf("error getting confirmed slot: %v", err)
+		return
+	}
+
+	confirmed, err := rpcClient.GetBlocks(ctx, first, &last, rpc.CommitmentConfirmed)
+	if err != nil {
+		log.Errorf("error getting confirmed blocks: %v", err)
+		return
+	}
+
+	finalized, err := rpcClient.GetBlocks(ctx, first, &last, rpc.CommitmentFinalized)
+	if err != nil {
+		log.Errorf("error getting finalized blocks: %v", err)
+		return
+	}
+
+	statuses := make(map[uint64]string)
+	for _, slot := range slots {
+		switch {
+		case slices.Contains(finalized, slot):
+			statuses[slot] = BlockStatusFinalized
+		case slot <= finalizedSlot:
+			statuses[slot] = BlockStatusDropped
+		case slices.Contains(confirmed, slot):
+			statuses[slot] = BlockStatusConfirmed
+		case slot <= confirmedSlot:
+			statuses[slot] = BlockStatusDropped
+		default:
+			statuses[slot] = BlockStatusProcessed
+		}
+	}
+
+	b.mu.Lock()
+	b.BlockStatuses = statuses
+	b.mu.Unlock()
+
+	if dropped, txs := b.DroppedBlocks(); dropped > 0 {
+		log.Warn("Transactions landed in dropped blocks", "blocks", dropped, "txs", txs)
+	}
+
+	b.VerifyLandings(ctx, rpcClient, finalizedSlot)
+}
+
+// VerifyLandings checks that the landings recorded up to the finalized slot were finalized in the same slot,
+// a landing whose transaction is missing or in another slot was on a block reorged out,
+// e.g. a duplicate slot replaced by another block, which a skipped slot check doesn't catch
+func (b *Benchmark) VerifyLandings(ctx context.Context, rpcClient *rpc.Client, finalizedSlot uint64) {
+	b.mu.RLock()
+	records := []*TxRecord{}
+	for _, record := range b.TxRecords {
+		if record.Landed && record.Slot <= finalizedSlot {
+			records = append(records, record)
+		}
+	}
+	b.mu.RUnlock()
+
+	var reorged, reincluded uint64
+	for start := 0; start < len(records); start += MaxSignatureStatuses {
+		batch := records[start:min(start+MaxSignatureStatuses, len(records))]
+
+		sigs := make([]solana.Signature, len(batch))
+		for i, record := range batch {
+			sigs[i] = record.Signature
+		}
+
+		// the landings of a long run may be out of the recent status cache
+		out, err := rpcClient.GetSignatureStatuses(ctx, true, sigs...)
+		if err != nil {
+			log.Errorf("error verifying the landings: %v", err)
+			return
+		}
+
+		b.mu.Lock()
+		for i, status := range out.Value {
+			record := batch[i]
+			if status != nil && status.Err == nil && status.Slot == record.Slot {
+				continue
+			}
+
+			record.Reorged = true
+			reorged += 1
+
+			// the transaction landed again, in a block of the canonical fork
+			if status != nil && status.Err == nil {
+				reincluded += 1
+			}
+		}
+		b.mu.Unlock()
+	}
+
+	b.mu.Lock()
+	b.LandingsVerified = uint64(len(records))
+	b.ReorgedLandings = reorged
+	b.ReincludedLandings = reincluded
+	b.mu.Unlock()
+
+	if reorged > 0 {
+		log.Warn("Transactions landed in blocks reorged out", "txs", reorged, "reincluded", reincluded, "verified", len(records))
+	}
+}
+
+// DroppedBlocks returns the number of dropped blocks, and the number of transactions they contained
+func (b *Benchmark) DroppedBlocks() (int, uint64) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	blocks := 0
+	var txs uint64
+	for slot, status := range b.BlockStatuses {
+		if status == BlockStatusDropped {
+			blocks += 1
+			txs += b.TxBlocks[slot]
+		}
+	}
+
+	return blocks, txs
+}
+
+// BlockStatusLabel returns the status of the block to display along with it, empty if unknown
+func (b *Benchmark) BlockStatusLabel(slot uint64) string {
+	status, ok := b.BlockStatuses[slot]
+	if !ok {
+		return ""
+	}
+
+	if status == BlockStatusDropped {
+		return fmt.Sprintf("%-9s | ", "DROPPED")
+	}
+
+	return fmt.Sprintf("%-9s | ", status)
+}
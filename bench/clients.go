@@ -0,0 +1,99 @@
+package bench
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+const (
+	// the settings of the proxied http client, the same as the default solana-go client
+	ProxyClientTimeout    = 5 * time.Minute
+	ProxyClientKeepAlive  = 180 * time.Second
+	ProxyClientMaxConns   = 9
+	ProxyClientTLSTimeout = 10 * time.Second
+)
+
+var (
+	// the proxy of the RPC and block engine requests, only set with proxy_url
+	Proxy *url.URL
+
+	// the http client shared by the proxied clients, so that they share the connections to the proxy
+	proxyHttpClient *http.Client
+)
+
+// newProxyHttpClient creates an http client going through the proxy, the local endpoints are reached directly
+func newProxyHttpClient(proxy *url.URL) *http.Client {
+	transport := &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			if isLocalHost(req.URL.Hostname()) {
+				return nil, nil
+			}
+
+			return proxy, nil
+		},
+		DialContext: (&net.Dialer{
+			Timeout:   ProxyClientTimeout,
+			KeepAlive: ProxyClientKeepAlive,
+		}).DialContext,
+		IdleConnTimeout:     ProxyClientTimeout,
+		MaxConnsPerHost:     ProxyClientMaxConns,
+		MaxIdleConnsPerHost: ProxyClientMaxConns,
+		ForceAttemptHTTP2:   true,
+		TLSHandshakeTimeout: ProxyClientTLSTimeout,
+	}
+
+	return &http.Client{Timeout: ProxyClientTimeout, Transport: transport}
+}
+
+// isLocalHost reports whether the host is localhost or a loopback address
+func isLocalHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// NewJsonRpcClient creates a json rpc client sending the headers along with each request,
+// through the proxy if set
+func NewJsonRpcClient(url string, headers map[string]string) jsonrpc.RPCClient {
+	opts := &jsonrpc.RPCClientOpts{CustomHeaders: headers}
+	if proxyHttpClient != nil {
+		opts.HTTPClient = proxyHttpClient
+	}
+
+	return jsonrpc.NewClientWithOpts(url, opts)
+}
+
+// NewRpcClient creates an rpc client sending the rpc_headers along with each request,
+// through the proxy if set
+func NewRpcClient(url string) *rpc.Client {
+	if proxyHttpClient != nil {
+		return rpc.NewWithCustomRPCClient(NewJsonRpcClient(url, GlobalConfig.RpcHeaders))
+	}
+
+	if len(GlobalConfig.RpcHeaders) == 0 {
+		return rpc.New(url)
+	}
+
+	return rpc.NewWithHeaders(url, GlobalConfig.RpcHeaders)
+}
+
+// ConnectWs connects to the websocket, sending the rpc_headers with the handshake,
+// the connection doesn't go through the proxy
+func ConnectWs(ctx context.Context, url string) (*ws.Client, error) {
+	header := http.Header{}
+	for name, value := range GlobalConfig.RpcHeaders {
+		header.Set(name, value)
+	}
+
+	return ws.ConnectWithOptions(ctx, url, &ws.Options{HttpHeader: header})
+}
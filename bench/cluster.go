@@ -0,0 +1,59 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+)
+
+// VerifyClusters returns an error if the urls of an endpoint point at different clusters,
+// in which case the transactions would never be seen by the listener
+func VerifyClusters(ctx context.Context) error {
+	for _, endpoint := range GlobalConfig.GetEndpoints() {
+		genesisHash, err := GetGenesisHash(ctx, endpoint.RpcUrl)
+		if err != nil {
+			return fmt.Errorf("error getting the genesis hash of rpc_url %s: %v", RedactUrl(endpoint.RpcUrl), err)
+		}
+
+		// the block engine doesn't serve getGenesisHash
+		for _, sendUrl := range endpoint.GetSendUrls() {
+			if GlobalConfig.GetSendMode() != SendModeRpc || sendUrl == endpoint.RpcUrl {
+				continue
+			}
+
+			sendHash, err := GetGenesisHash(ctx, sendUrl)
+			if err != nil {
+				return fmt.Errorf("error getting the genesis hash of send url %s: %v", RedactUrl(sendUrl), err)
+			}
+
+			if sendHash != genesisHash {
+				return fmt.Errorf("send url %s is on a different cluster than rpc_url %s (genesis hash %s vs %s)", RedactUrl(sendUrl), RedactUrl(endpoint.RpcUrl), sendHash, genesisHash)
+			}
+		}
+
+		// the websocket only serves subscriptions, the genesis hash is queried
+		// over http on the same host, which most providers support
+		if endpoint.WsUrl != "" {
+			wsHttpUrl := strings.Replace(strings.Replace(endpoint.WsUrl, "wss://", "https://", 1), "ws://", "http://", 1)
+
+			wsHash, err := GetGenesisHash(ctx, wsHttpUrl)
+			if err != nil {
+				log.Warn("Unable to verify the cluster of the websocket endpoint", "ws_url", RedactUrl(endpoint.WsUrl), "err", err)
+				continue
+			}
+
+			if wsHash != genesisHash {
+				return fmt.Errorf("ws_url %s is on a different cluster than rpc_url %s (genesis hash %s vs %s)", RedactUrl(endpoint.WsUrl), RedactUrl(endpoint.RpcUrl), wsHash, genesisHash)
+			}
+		}
+	}
+
+	return nil
+}
+
+func GetGenesisHash(ctx context.Context, rpcUrl string) (solana.Hash, error) {
+	return NewRpcClient(rpcUrl).GetGenesisHash(ctx)
+}
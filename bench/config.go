@@ -0,0 +1,807 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// default compute unit limit, used when compute_unit_limit is not set
+	DefaultComputeUnitLimit = 30000
+
+	// maximum compute units a transaction can request
+	MaxComputeUnitLimit = 1_400_000
+
+	// websocket reconnection attempts and the initial delay between them
+	MaxReconnectAttempts = 5
+	ReconnectBaseDelay   = time.Second
+
+	// error code of the RPC when the simulation of a transaction failed
+	PreflightFailureCode = -32002
+
+	// error code of the RPC when the node is behind the cluster
+	NodeUnhealthyCode = -32005
+
+	// maximum serialized size of a transaction
+	MaxTxSize = 1232
+
+	// maximum number of slots per getSlotLeaders request
+	MaxSlotLeaders = 5000
+
+	// maximum number of signatures per getSignatureStatuses request
+	MaxSignatureStatuses = 256
+
+	// confirm modes, ws listens for the landings on the websocket,
+	// poll polls the signature statuses, both uses the two at once
+	ConfirmModeWs   = "ws"
+	ConfirmModePoll = "poll"
+	ConfirmModeBoth = "both"
+
+	// sources of the landings streamed in ws and both confirm modes,
+	// the websocket logs subscriptions or a yellowstone geyser grpc subscription
+	ConfirmSourceWs        = "ws"
+	ConfirmSourceGeyser    = "geyser"
+	ConfirmSourceBlock     = "block"
+	ConfirmSourceSignature = "signature"
+
+	// default maximum number of signature subscriptions open at once with the signature confirm source
+	DefaultMaxSignatureSubscriptions = 1000
+
+	// split modes, to send and listen from different machines sharing the test id
+	SplitModeSendOnly   = "send_only"
+	SplitModeListenOnly = "listen_only"
+
+	// transaction versions
+	TxVersionLegacy = "legacy"
+	TxVersionV0     = "0"
+
+	// default interval between two blockhash refreshes, well within the blockhash lifetime
+	DefaultBlockhashRefreshInterval = 30
+
+	// default time to wait for the RPC to accept a transaction, in seconds
+	DefaultSendTimeout = 10
+
+	// default log level, the per-transaction logs are at the debug level
+	DefaultLogLevel = "info"
+
+	// default format of the log timestamps
+	DefaultLogTimeFormat = "15:04:05.0000"
+
+	// formats of the log file
+	LogFormatText = "text"
+	LogFormatJson = "json"
+
+	// formats of the results summary, set with -format
+	SummaryFormatPlain = "plain"
+	SummaryFormatTable = "table"
+
+	// rate limit backoff, each level halves the send rate,
+	// the concurrent rate limited sends within the interval only back off once
+	MaxBackoffLevel          = 5
+	MaxRateLimitRetries      = 3
+	RateLimitBackoffInterval = time.Second
+	RateLimitRecoveryDelay   = 5 * time.Second
+
+	// default number of steps the rate is raised in from rate_start to rate_end
+	DefaultRateSteps = 10
+
+	// default number of compute unit price levels swept from fee_min to fee_max
+	DefaultFeeSteps = 10
+
+	// the results webhook attempts, the delay before the first retry doubles after each one,
+	// and the time to wait for the webhook to respond to each attempt
+	MaxWebhookAttempts = 3
+	WebhookRetryDelay  = time.Second
+	WebhookTimeout     = 5 * time.Second
+
+	// the attempts of the startup rpc requests, e.g. the blockhash fetch and the balance check,
+	// the delay before the first retry doubles after each one
+	MaxRpcAttempts = 4
+	RpcRetryDelay  = 500 * time.Millisecond
+
+	// average wait for the rate limiter past which the summary hints that rate_limit is the bottleneck
+	ThrottleHintDelay = time.Second
+
+	// time left to the last control transactions to land before their statuses are swept
+	ControlSettleDelay = 5 * time.Second
+
+	// pause between two repeated runs
+	RepeatDelay = 5 * time.Second
+
+	// interval between two progress lines while the test runs
+	ProgressInterval = 5 * time.Second
+
+	// a blockhash expires after 150 blocks, the run keeps listening for
+	// a few more blocks for the late notifications of the last transactions
+	BlockhashValidBlocks  = 150
+	BlockhashExpiryMargin = 10
+
+	// interval between two blockhash expiry checks once all the transactions are sent
+	ExpiryCheckInterval = time.Second
+
+	// interval between two checks of the number of sent transactions, before the early abort check
+	EarlyAbortCheckInterval = time.Second
+
+	// time the last transaction of the early abort window is given to land before the check
+	EarlyAbortGracePeriod = 30 * time.Second
+
+	// the expected slot time, used to estimate the end of the run when the slots can't be followed
+	SlotDuration = 400 * time.Millisecond
+
+	// default width of the landing time histogram buckets, in milliseconds
+	DefaultHistogramBucket = 100
+
+	// maximum number of landing time histogram buckets, the slower landings are grouped in the last one
+	MaxHistogramBuckets = 50
+
+	// relative error of the quantiles computed with streaming_stats
+	SketchRelativeError = 0.01
+
+	// interval between two signature statuses polls
+	StatusPollInterval = time.Second
+
+	// interval between two signature statuses polls of the lifecycle tracker,
+	// shorter than the status poller since it bounds the precision of the measures
+	LifecyclePollInterval = 200 * time.Millisecond
+
+	// how long the first transaction of an incomplete send batch waits for the batch to fill up
+	SendBatchMaxWait = 50 * time.Millisecond
+
+	// how long the lifecycle tracker waits for the landed transactions to be finalized after the run
+	FinalizationTimeout = time.Minute
+
+	// priority fee modes, static uses compute_unit_price_microlamports as is
+	// dynamic derives it from the recent prioritization fees
+	PrioFeeModeStatic  = "static"
+	PrioFeeModeDynamic = "dynamic"
+
+	// send modes, rpc sends the transactions through the RPC
+	// jito submits them as bundles to a jito block engine
+	SendModeRpc  = "rpc"
+	SendModeJito = "jito"
+
+	// minimum tip accepted by the jito block engine, in lamports
+	MinJitoTip = 1000
+
+	// base fee of a transaction with a single signature, in lamports
+	BaseFeeLamports = 5000
+
+	// the compute unit price is in micro-lamports
+	MicroLamportsPerLamport = 1_000_000
+
+	// default percentile of the recent fees used in dynamic mode
+	DefaultPrioFeePercentile = 50
+
+	// maximum number of dropped transaction signatures listed in the summary
+	MaxDisplayedDropped = 50
+
+	// memo prefix of the warmup transactions, which aren't measured
+	WarmupMemoPrefix = "memobench: Warmup"
+
+	// memo prefix of the transactions of the control stream, which are measured apart from the workload
+	ControlMemoPrefix = "memobench: Control"
+
+	// environment variable that takes precedence over the private_key config field
+	PrivateKeyEnvVar = "MEMOBENCH_PRIVATE_KEY"
+)
+
+var (
+	DEFAULT_CONFIG = Config{
+		RpcUrl:           "http://node.foo.cc",
+		RateLimit:        200,
+		TxCount:          100,
+		ComputeUnitLimit: DefaultComputeUnitLimit,
+		Commitment:       string(rpc.CommitmentProcessed),
+		MemoTemplate:     DefaultMemoTemplate,
+	}
+
+	// the config of the test, set by Run
+	GlobalConfig *Config
+
+	// default landing time percentiles of the summary
+	DefaultPercentiles = []float64{90, 95, 99}
+)
+
+type Config struct {
+	PrivateKey           string    `json:"private_key"`
+	PrivateKeys          []string  `json:"private_keys,omitempty"`
+	RpcUrl               string    `json:"rpc_url"`
+	WsUrl                string    `json:"ws_url"`
+	SendRpcUrl           string    `json:"send_rpc_url"`
+	SendRpcUrls          []string  `json:"send_rpc_urls,omitempty"`
+	RateLimit            uint64    `json:"rate_limit"`
+	TxCount              uint64    `json:"tx_count"`
+	PrioFee              float64   `json:"prio_fee,omitempty"` // deprecated, use compute_unit_price_microlamports
+	ComputeUnitPrice     uint64    `json:"compute_unit_price_microlamports"`
+	NodeRetries          uint      `json:"node_retries"`
+	ComputeUnitLimit     uint32    `json:"compute_unit_limit"`
+	Duration             uint64    `json:"duration"`
+	Commitment           string    `json:"commitment"`
+	ApplyCommitmentToRpc bool      `json:"apply_commitment_to_rpc"`
+	BlockhashCommitment  string    `json:"blockhash_commitment"`
+	PrioFeeMode          string    `json:"prio_fee_mode"`
+	PrioFeePercentile    float64   `json:"prio_fee_percentile"`
+	WarmupTxCount        uint64    `json:"warmup_tx_count"`
+	ControlRate          uint64    `json:"control_rate"`
+	ControlCuPrice       uint64    `json:"control_compute_unit_price_microlamports"`
+	MetricsAddr          string    `json:"metrics_addr"`
+	OtelEndpoint         string    `json:"otel_endpoint"`
+	MemoTemplate         string    `json:"memo_template"`
+	MemoProgramId        string    `json:"memo_program_id"`
+	Workload             string    `json:"workload"`
+	WorkloadProgramId    string    `json:"workload_program_id"`
+	WorkloadDataSize     uint64    `json:"workload_data_size"`
+	TxSizeBytes          uint64    `json:"tx_size_bytes"`
+	SendMode             string    `json:"send_mode"`
+	JitoUrl              string    `json:"jito_url"`
+	JitoTip              uint64    `json:"jito_tip"`
+	ConfirmMode          string    `json:"confirm_mode"`
+	ConfirmSource        string    `json:"confirm_source"`
+	GeyserUrl            string    `json:"geyser_url"`
+	GeyserToken          string    `json:"geyser_token"`
+	TxVersion            TxVersion `json:"tx_version"`
+	LookupTable          string    `json:"lookup_table"`
+	StartDelay           *float64  `json:"start_delay,omitempty"`
+	BlockhashRefresh     uint64    `json:"blockhash_refresh"`
+	SendTimeout          float64   `json:"send_timeout"`
+	HistogramBucket      uint64    `json:"histogram_bucket_ms"`
+	Percentiles          []float64 `json:"percentiles,omitempty"`
+	LogLevel             string    `json:"log_level"`
+	LogFormat            string    `json:"log_format"`
+	LogTimezone          string    `json:"log_timezone"`
+	LogTimeFormat        string    `json:"log_time_format"`
+	DryRun               bool      `json:"dry_run"`
+	Repeat               uint64    `json:"repeat"`
+	ProxyUrl             string    `json:"proxy_url"`
+	LogDir               string    `json:"log_dir"`
+	SkipPreflight        *bool     `json:"skip_preflight,omitempty"`
+	PreflightCommitment  string    `json:"preflight_commitment"`
+	MinLandingRate       float64   `json:"min_landing_rate"`
+	EarlyAbortWindow     uint64    `json:"early_abort_window"`
+	EarlyAbortThreshold  float64   `json:"early_abort_threshold"`
+	TrackAllCommitments  bool      `json:"track_all_commitments"`
+	Burst                uint64    `json:"burst"`
+	SendWindow           float64   `json:"send_window"`
+	SkipBalanceCheck     bool      `json:"skip_balance_check"`
+	AutoFund             bool      `json:"auto_fund"`
+	VerifyBlocks         bool      `json:"verify_blocks"`
+	SendBatchSize        uint64    `json:"send_batch_size"`
+	Workers              uint64    `json:"workers"`
+	SplitMode            string    `json:"split_mode"`
+	TestID               string    `json:"test_id"`
+	StreamingStats       bool      `json:"streaming_stats"`
+	EventsFile           bool      `json:"events_file"`
+	RateStart            uint64    `json:"rate_start"`
+	RateEnd              uint64    `json:"rate_end"`
+	RateSteps            uint64    `json:"rate_steps"`
+	FeeMin               uint64    `json:"fee_min"`
+	FeeMax               uint64    `json:"fee_max"`
+	FeeSteps             uint64    `json:"fee_steps"`
+	WebhookUrl           string    `json:"webhook_url"`
+	ResultsOutput        string    `json:"results_output"`
+	BlockLatencies       bool      `json:"block_latencies"`
+	CrossCheckWsUrl      string    `json:"cross_check_ws_url"`
+	MaxResends           uint64    `json:"max_resends"`
+	ApplySuggestedFee    bool      `json:"apply_suggested_fee"`
+	NoLogFile            bool      `json:"no_log_file"`
+
+	// cap of the signature subscriptions open at once with the signature confirm source
+	MaxSignatureSubscriptions uint64 `json:"max_signature_subscriptions"`
+
+	// headers sent with the rpc and websocket requests, e.g. an api key
+	RpcHeaders map[string]string `json:"rpc_headers,omitempty"`
+
+	// endpoints to compare, takes precedence over rpc_url, ws_url and send_rpc_url
+	Endpoints []Endpoint `json:"endpoints,omitempty"`
+}
+
+type Endpoint struct {
+	Label      string `json:"label"`
+	RpcUrl     string `json:"rpc_url"`
+	WsUrl      string `json:"ws_url"`
+	SendRpcUrl string `json:"send_rpc_url"`
+
+	// the transactions are spread across these urls, takes precedence over send_rpc_url
+	SendRpcUrls []string `json:"send_rpc_urls,omitempty"`
+}
+
+// TxVersion is the version of the transactions, either "legacy" or 0
+type TxVersion string
+
+func (v TxVersion) MarshalJSON() ([]byte, error) {
+	if v == TxVersionV0 {
+		return []byte(TxVersionV0), nil
+	}
+
+	return json.Marshal(string(v))
+}
+
+// UnmarshalJSON accepts both the numeric and the string forms of the version
+func (v *TxVersion) UnmarshalJSON(data []byte) error {
+	*v = TxVersion(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// GetEndpoints returns the endpoints to benchmark, in order
+func (c *Config) GetEndpoints() []Endpoint {
+	if len(c.Endpoints) > 0 {
+		return c.Endpoints
+	}
+
+	return []Endpoint{{RpcUrl: c.RpcUrl, WsUrl: c.WsUrl, SendRpcUrl: c.SendRpcUrl, SendRpcUrls: c.SendRpcUrls}}
+}
+
+func (e *Endpoint) GetLabel() string {
+	if e.Label != "" {
+		return e.Label
+	}
+
+	// fallback to the RPC host
+	if u, err := url.Parse(e.RpcUrl); err == nil && u.Host != "" {
+		return u.Host
+	}
+
+	return e.RpcUrl
+}
+
+func (e *Endpoint) GetWsUrl() string {
+	if e.WsUrl != "" {
+		return e.WsUrl
+	}
+
+	// replace http:// with ws:// and https:// with wss://
+	return strings.ReplaceAll(strings.ReplaceAll(e.RpcUrl, "http://", "ws://"), "https://", "wss://")
+}
+
+// GetSendUrls returns the urls the transactions are sent to, in order
+func (e *Endpoint) GetSendUrls() []string {
+	if len(e.SendRpcUrls) > 0 {
+		return e.SendRpcUrls
+	}
+
+	return []string{e.GetSendUrl()}
+}
+
+func (e *Endpoint) GetSendUrl() string {
+	if e.SendRpcUrl != "" {
+		return e.SendRpcUrl
+	}
+
+	return e.RpcUrl
+}
+
+// GetComputeUnitPrice returns the static compute unit price in micro-lamports,
+// falling back to the deprecated prio_fee, in lamports per compute unit
+func (c *Config) GetComputeUnitPrice() uint64 {
+	if c.ComputeUnitPrice != 0 {
+		return c.ComputeUnitPrice
+	}
+
+	return uint64(math.Round(c.PrioFee * MicroLamportsPerLamport))
+}
+
+// EstimateTxCost returns the fee of a transaction in lamports, for the given compute unit price in micro-lamports
+func EstimateTxCost(computeUnitPrice uint64) uint64 {
+	prioFee := (computeUnitPrice*uint64(GlobalConfig.GetComputeUnitLimit()) + MicroLamportsPerLamport - 1) / MicroLamportsPerLamport
+
+	return BaseFeeLamports + prioFee
+}
+
+// FormatComputeUnitPrice formats the compute unit price along with the resulting transaction fee
+func FormatComputeUnitPrice(computeUnitPrice uint64) string {
+	return fmt.Sprintf("%d micro-lamports (%.9f SOL/tx)", computeUnitPrice, float64(EstimateTxCost(computeUnitPrice))/float64(solana.LAMPORTS_PER_SOL))
+}
+
+func (c *Config) GetComputeUnitLimit() uint32 {
+	if c.ComputeUnitLimit != 0 {
+		return c.ComputeUnitLimit
+	}
+
+	return DefaultComputeUnitLimit
+}
+
+// GetCommitment returns the commitment level at which transactions are considered landed
+func (c *Config) GetCommitment() rpc.CommitmentType {
+	if c.Commitment != "" {
+		return rpc.CommitmentType(c.Commitment)
+	}
+
+	return rpc.CommitmentProcessed
+}
+
+// GetRpcCommitment returns the commitment level used for the balance check and blockhash fetch
+func (c *Config) GetRpcCommitment() rpc.CommitmentType {
+	if c.ApplyCommitmentToRpc {
+		return c.GetCommitment()
+	}
+
+	return rpc.CommitmentFinalized
+}
+
+// GetBlockhashCommitment returns the commitment level used for the blockhash fetch
+func (c *Config) GetBlockhashCommitment() rpc.CommitmentType {
+	if c.BlockhashCommitment != "" {
+		return rpc.CommitmentType(c.BlockhashCommitment)
+	}
+
+	return c.GetRpcCommitment()
+}
+
+func (c *Config) GetPrioFeeMode() string {
+	if c.PrioFeeMode != "" {
+		return c.PrioFeeMode
+	}
+
+	return PrioFeeModeStatic
+}
+
+func (c *Config) GetSendMode() string {
+	if c.SendMode != "" {
+		return c.SendMode
+	}
+
+	return SendModeRpc
+}
+
+func (c *Config) GetConfirmMode() string {
+	if c.ConfirmMode != "" {
+		return c.ConfirmMode
+	}
+
+	return ConfirmModeWs
+}
+
+func (c *Config) GetConfirmSource() string {
+	if c.ConfirmSource != "" {
+		return c.ConfirmSource
+	}
+
+	return ConfirmSourceWs
+}
+
+func (c *Config) GetTxVersion() TxVersion {
+	if c.TxVersion != "" {
+		return c.TxVersion
+	}
+
+	return TxVersionLegacy
+}
+
+func (c *Config) GetPrioFeePercentile() float64 {
+	if c.PrioFeePercentile != 0 {
+		return c.PrioFeePercentile
+	}
+
+	return DefaultPrioFeePercentile
+}
+
+// GetMemoProgramId returns the program the memos are written with, the spl memo v2 program by default
+func (c *Config) GetMemoProgramId() solana.PublicKey {
+	if c.MemoProgramId != "" {
+		return solana.MustPublicKeyFromBase58(c.MemoProgramId)
+	}
+
+	return solana.MemoProgramID
+}
+
+// LogsMemos reports whether the memo program logs the memos, so that the landings can be matched by memo,
+// only the spl memo v2 program is known to do so, the landings are matched by signature otherwise
+func (c *Config) LogsMemos() bool {
+	return c.GetMemoProgramId().Equals(solana.MemoProgramID)
+}
+
+func (c *Config) GetMemoTemplate() string {
+	if c.MemoTemplate != "" {
+		return c.MemoTemplate
+	}
+
+	return DefaultMemoTemplate
+}
+
+// GetBlockhashRefreshInterval returns the interval between two blockhash refreshes
+func (c *Config) GetBlockhashRefreshInterval() time.Duration {
+	if c.BlockhashRefresh != 0 {
+		return time.Duration(c.BlockhashRefresh) * time.Second
+	}
+
+	return DefaultBlockhashRefreshInterval * time.Second
+}
+
+// GetSendTimeout returns the time to wait for the RPC to accept a transaction
+func (c *Config) GetSendTimeout() time.Duration {
+	if c.SendTimeout != 0 {
+		return time.Duration(c.SendTimeout * float64(time.Second))
+	}
+
+	return DefaultSendTimeout * time.Second
+}
+
+// GetSendWindow returns the window the transactions are spread across, 0 sends them at once
+func (c *Config) GetSendWindow() time.Duration {
+	return time.Duration(c.SendWindow * float64(time.Second))
+}
+
+// GetPercentiles returns the landing time percentiles shown in the summary
+func (c *Config) GetPercentiles() []float64 {
+	if len(c.Percentiles) > 0 {
+		return c.Percentiles
+	}
+
+	return DefaultPercentiles
+}
+
+// GetHistogramBucket returns the width of the landing time histogram buckets
+func (c *Config) GetHistogramBucket() time.Duration {
+	if c.HistogramBucket != 0 {
+		return time.Duration(c.HistogramBucket) * time.Millisecond
+	}
+
+	return DefaultHistogramBucket * time.Millisecond
+}
+
+func (c *Config) GetLogLevel() string {
+	if c.LogLevel != "" {
+		return c.LogLevel
+	}
+
+	return DefaultLogLevel
+}
+
+func (c *Config) GetLogFormat() string {
+	if c.LogFormat != "" {
+		return c.LogFormat
+	}
+
+	return LogFormatText
+}
+
+func (c *Config) GetLogTimeFormat() string {
+	if c.LogTimeFormat != "" {
+		return c.LogTimeFormat
+	}
+
+	return DefaultLogTimeFormat
+}
+
+// GetRepeat returns the number of times the test is run
+func (c *Config) GetRepeat() uint64 {
+	if c.Repeat != 0 {
+		return c.Repeat
+	}
+
+	return 1
+}
+
+// GetSkipPreflight reports whether the RPC skips the simulation of the transactions, the default
+func (c *Config) GetSkipPreflight() bool {
+	if c.SkipPreflight != nil {
+		return *c.SkipPreflight
+	}
+
+	return true
+}
+
+func (c *Config) GetWorkload() string {
+	if c.Workload != "" {
+		return c.Workload
+	}
+
+	return WorkloadMemo
+}
+
+func (c *Config) GetWorkloadDataSize() uint64 {
+	if c.WorkloadDataSize != 0 {
+		return c.WorkloadDataSize
+	}
+
+	return DefaultWorkloadDataSize
+}
+
+// GetBurst returns the number of transactions that can be sent at once, defaults to the rate limit
+func (c *Config) GetBurst() uint64 {
+	if c.Burst != 0 {
+		return c.Burst
+	}
+
+	return c.GetRateLimit()
+}
+
+// IsRamp reports whether the rate is ramped up from rate_start to rate_end instead of rate_limit
+func (c *Config) IsRamp() bool {
+	return c.RateStart > 0 || c.RateEnd > 0
+}
+
+// GetRateLimit returns the highest send rate of the test, the rate_limit or the highest rate of the ramp
+func (c *Config) GetRateLimit() uint64 {
+	if c.IsRamp() {
+		return max(c.RateStart, c.RateEnd)
+	}
+
+	return c.RateLimit
+}
+
+func (c *Config) GetRateSteps() uint64 {
+	if c.RateSteps != 0 {
+		return c.RateSteps
+	}
+
+	return DefaultRateSteps
+}
+
+// RampRate returns the send rate of the step of the ramp, the first step is sent at rate_start and the last one at rate_end
+func (c *Config) RampRate(step uint64) uint64 {
+	steps := c.GetRateSteps()
+	if step >= steps-1 {
+		return c.RateEnd
+	}
+
+	return uint64(int64(c.RateStart) + (int64(c.RateEnd)-int64(c.RateStart))*int64(step)/int64(steps-1))
+}
+
+// IsFeeSweep reports whether the compute unit price is swept from fee_min to fee_max across the transactions
+func (c *Config) IsFeeSweep() bool {
+	return c.FeeMax > 0
+}
+
+func (c *Config) GetFeeSteps() uint64 {
+	if c.FeeSteps != 0 {
+		return c.FeeSteps
+	}
+
+	return DefaultFeeSteps
+}
+
+// FeeLevel returns the compute unit price of the step of the sweep, the first step is sent at fee_min and the last one at fee_max
+func (c *Config) FeeLevel(step uint64) uint64 {
+	steps := c.GetFeeSteps()
+	if step >= steps-1 {
+		return c.FeeMax
+	}
+
+	return c.FeeMin + (c.FeeMax-c.FeeMin)*step/(steps-1)
+}
+
+// FeeStepFor returns the step of the sweep of the transaction with the given number, the expected transactions
+// are split in steps of equal size, the ones past the expected count are sent at fee_max
+func (c *Config) FeeStepFor(id uint64) uint64 {
+	count := max(c.GetExpectedTxCount()-c.WarmupTxCount, 1)
+
+	return min((id-1)*c.GetFeeSteps()/count, c.GetFeeSteps()-1)
+}
+
+func (c *Config) GetMaxSignatureSubscriptions() uint64 {
+	if c.MaxSignatureSubscriptions != 0 {
+		return c.MaxSignatureSubscriptions
+	}
+
+	return DefaultMaxSignatureSubscriptions
+}
+
+// GetSpamStartTime returns the time to start sending the transactions,
+// by default the start is aligned to the 5s boundary following a 10s lead time
+func (c *Config) GetSpamStartTime(now time.Time) time.Time {
+	if c.StartDelay != nil {
+		return now.Add(time.Duration(*c.StartDelay * float64(time.Second)))
+	}
+
+	// the start is offset from now rather than truncated, to keep the monotonic clock reading
+	aligned := now.Truncate(5 * time.Second).Add(10 * time.Second)
+	return now.Add(aligned.Sub(now))
+}
+
+// GetExpectedTxCount returns the number of transactions the test is expected to send, including the warmup
+func (c *Config) GetExpectedTxCount() uint64 {
+	// each step of the ramp lasts an equal share of the duration
+	if c.Duration > 0 && c.IsRamp() {
+		total := uint64(0)
+		for step := uint64(0); step < c.GetRateSteps(); step++ {
+			total += c.RampRate(step)
+		}
+
+		return c.Duration*total/c.GetRateSteps() + c.WarmupTxCount
+	}
+
+	if c.Duration > 0 {
+		return c.Duration*c.RateLimit + c.WarmupTxCount
+	}
+
+	return c.TxCount + c.WarmupTxCount
+}
+
+// GetExpectedControlTxCount returns the number of transactions the control stream is expected to send,
+// while the workload is sent
+func (c *Config) GetExpectedControlTxCount() uint64 {
+	if c.ControlRate == 0 {
+		return 0
+	}
+
+	seconds := c.Duration
+	if seconds == 0 {
+		seconds = max(c.TxCount/c.GetRateLimit(), uint64(c.SendWindow)) + 1
+	}
+
+	return seconds * c.ControlRate
+}
+
+// RedactUrl hides the password of the url, if any
+func RedactUrl(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return rawUrl
+	}
+
+	return u.Redacted()
+}
+
+// Redacted returns a copy of the config that is safe to be shared
+func (c *Config) Redacted() Config {
+	out := *c
+	if out.PrivateKey != "" {
+		out.PrivateKey = "[REDACTED]"
+	}
+
+	// the proxy credentials, if any
+	if out.ProxyUrl != "" {
+		out.ProxyUrl = RedactUrl(out.ProxyUrl)
+	}
+
+	if out.GeyserUrl != "" {
+		out.GeyserUrl = RedactUrl(out.GeyserUrl)
+	}
+	if out.CrossCheckWsUrl != "" {
+		out.CrossCheckWsUrl = RedactUrl(out.CrossCheckWsUrl)
+	}
+	if out.OtelEndpoint != "" {
+		out.OtelEndpoint = RedactUrl(out.OtelEndpoint)
+	}
+	if out.GeyserToken != "" {
+		out.GeyserToken = "[REDACTED]"
+	}
+
+	// the path of a webhook url is usually its secret
+	if out.WebhookUrl != "" {
+		out.WebhookUrl = "[REDACTED]"
+	}
+	if out.ResultsOutput != "" {
+		out.ResultsOutput = RedactUrl(out.ResultsOutput)
+	}
+
+	// the header values usually hold the credentials
+	if len(out.RpcHeaders) > 0 {
+		out.RpcHeaders = make(map[string]string, len(c.RpcHeaders))
+		for name := range c.RpcHeaders {
+			out.RpcHeaders[name] = "[REDACTED]"
+		}
+	}
+
+	if len(out.PrivateKeys) > 0 {
+		out.PrivateKeys = make([]string, len(c.PrivateKeys))
+		for i := range out.PrivateKeys {
+			out.PrivateKeys[i] = "[REDACTED]"
+		}
+	}
+
+	return out
+}
+
+func ApplyEnv(config *Config) {
+	envKey := strings.TrimSpace(os.Getenv(PrivateKeyEnvVar))
+	if envKey == "" {
+		return
+	}
+
+	if config.PrivateKey != "" && config.PrivateKey != envKey {
+		log.Warn("Private key in config file differs from the environment variable, using the environment variable", "env", PrivateKeyEnvVar)
+	}
+
+	config.PrivateKey = envKey
+}
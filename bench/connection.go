@@ -0,0 +1,60 @@
+package bench
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// ConnectionTimes holds the time it took to reach an endpoint from a cold connection,
+// which is part of the latency of the first transactions but not of the following ones
+type ConnectionTimes struct {
+	// the first RPC request, including the TCP and TLS handshakes, 0 if it failed
+	RpcRoundTrip time.Duration
+
+	// the websocket handshake, 0 if it failed
+	WsConnect time.Duration
+}
+
+// the connection times of each endpoint, by label
+var EndpointConnectionTimes = make(map[string]ConnectionTimes)
+
+// MeasureConnections times a fresh RPC request and websocket connection to each endpoint,
+// the failures are only logged since the run reports them anyway
+func MeasureConnections(ctx context.Context) {
+	for _, endpoint := range GlobalConfig.GetEndpoints() {
+		times := ConnectionTimes{}
+
+		start := time.Now()
+		if _, err := NewRpcClient(endpoint.RpcUrl).GetVersion(ctx); err != nil {
+			log.Warn("Unable to measure the RPC round trip", "rpc_url", RedactUrl(endpoint.RpcUrl), "err", err)
+		} else {
+			times.RpcRoundTrip = time.Since(start)
+		}
+
+		start = time.Now()
+		if wsClient, err := ConnectWs(ctx, endpoint.GetWsUrl()); err != nil {
+			log.Warn("Unable to measure the websocket connection", "ws_url", RedactUrl(endpoint.GetWsUrl()), "err", err)
+		} else {
+			times.WsConnect = time.Since(start)
+			wsClient.Close()
+		}
+
+		EndpointConnectionTimes[endpoint.GetLabel()] = times
+
+		if len(GlobalConfig.GetEndpoints()) > 1 {
+			HeaderLogger.Printf("Endpoint            : %s", endpoint.GetLabel())
+		}
+		HeaderLogger.Printf("RPC Round Trip      : %s", formatConnectionTime(times.RpcRoundTrip))
+		HeaderLogger.Printf("WS Connect Time     : %s", formatConnectionTime(times.WsConnect))
+	}
+}
+
+func formatConnectionTime(d time.Duration) string {
+	if d == 0 {
+		return "unknown"
+	}
+
+	return d.Truncate(time.Millisecond).String()
+}
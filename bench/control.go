@@ -0,0 +1,232 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ControlStream sends a small stream of transactions at a known-good fee alongside the workload,
+// its landing rate tells a congested cluster apart from a bad endpoint or a too low fee
+type ControlStream struct {
+	Bench *Benchmark
+
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	records  map[solana.Signature]*TxRecord
+	lastSend time.Time
+
+	Sent   uint64
+	Landed uint64
+	Deltas []time.Duration
+}
+
+func NewControlStream(bench *Benchmark) *ControlStream {
+	return &ControlStream{
+		Bench:   bench,
+		records: make(map[solana.Signature]*TxRecord),
+		Deltas:  []time.Duration{},
+	}
+}
+
+// Start sends the control transactions at control_rate from the start of the workload,
+// until the workload is sent or the run is stopped
+func (c *ControlStream) Start(sendClient *rpc.Client) {
+	b := c.Bench
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		if !b.Sleep(time.Until(b.SpamStartTime)) {
+			return
+		}
+
+		ticker := time.NewTicker(time.Second / time.Duration(GlobalConfig.ControlRate))
+		defer ticker.Stop()
+
+		for id := uint64(1); ; id++ {
+			b.mu.RLock()
+			done := b.SendingDone
+			b.mu.RUnlock()
+
+			if done {
+				return
+			}
+
+			c.wg.Add(1)
+			go func(id uint64) {
+				defer c.wg.Done()
+				c.send(sendClient, id)
+			}(id)
+
+			select {
+			case <-b.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *ControlStream) send(sendClient *rpc.Client, id uint64) {
+	b := c.Bench
+
+	wallet := WalletFor(id)
+	memo := fmt.Sprintf("%s %d [%s]", ControlMemoPrefix, id, b.TestID)
+	tx := b.assembleTransaction(MemoInstruction(GlobalConfig.GetMemoProgramId(), memo, wallet.PublicKey()), nil, b.LatestBlockhash(), wallet, GlobalConfig.ControlCuPrice)
+
+	// the record is known and counted before the send, the landing may be notified before the send returns
+	record := &TxRecord{Num: id, Signature: tx.Signatures[0], SendTime: time.Now()}
+	c.mu.Lock()
+	c.records[record.Signature] = record
+	c.Sent += 1
+	c.mu.Unlock()
+
+	if _, err := b.Send(sendClient, tx); err != nil {
+		log.Debug("Error sending control tx", "num", id, "err", err)
+
+		// a transaction that landed despite the error was sent, the others are forgotten
+		c.mu.Lock()
+		if !record.Landed {
+			delete(c.records, record.Signature)
+			c.Sent -= 1
+		}
+		c.mu.Unlock()
+		return
+	}
+
+	c.mu.Lock()
+	c.lastSend = time.Now()
+	c.mu.Unlock()
+}
+
+// RecordLanding records the landing of the control transaction, it returns false if the signature
+// isn't one of the control stream
+func (c *ControlStream) RecordLanding(sig solana.Signature, slot uint64) bool {
+	return c.recordLanding(sig, slot, false)
+}
+
+// recordLanding records the landing, without the landing time if it was recovered by the sweep
+func (c *ControlStream) recordLanding(sig solana.Signature, slot uint64, backfilled bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	record, ok := c.records[sig]
+	if !ok {
+		return false
+	}
+
+	// the landing may be notified by several sources
+	if record.Landed {
+		return true
+	}
+
+	record.Landed = true
+	record.Slot = slot
+	record.Backfilled = backfilled
+	c.Landed += 1
+
+	if !backfilled {
+		record.Delta = time.Since(record.SendTime)
+		c.Deltas = append(c.Deltas, record.Delta)
+	}
+
+	log.Debug("Control tx processed", "num", record.Num, "sig", sig.String(), "landed", fmt.Sprintf("%d/%d", c.Landed, c.Sent))
+
+	return true
+}
+
+// Sweep waits for the last control transactions to settle, and records the landings missed
+// by the confirmation sources, it waits up to ControlSettleDelay after the last control transaction
+func (c *ControlStream) Sweep(ctx context.Context, rpcClient *rpc.Client) {
+	c.wg.Wait()
+
+	c.mu.Lock()
+	lastSend := c.lastSend
+	pending := []solana.Signature{}
+	for sig, record := range c.records {
+		if !record.Landed {
+			pending = append(pending, sig)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(time.Until(lastSend.Add(ControlSettleDelay))):
+	}
+
+	for start := 0; start < len(pending); start += MaxSignatureStatuses {
+		batch := pending[start:min(start+MaxSignatureStatuses, len(pending))]
+
+		out, err := rpcClient.GetSignatureStatuses(ctx, false, batch...)
+		if err != nil {
+			log.Errorf("error getting control signature statuses: %v", err)
+			continue
+		}
+
+		for i, status := range out.Value {
+			if status == nil || status.Err != nil || !CommitmentReached(status.ConfirmationStatus, GlobalConfig.GetCommitment()) {
+				continue
+			}
+
+			c.recordLanding(batch[i], status.Slot, true)
+		}
+	}
+}
+
+// LandingRate returns the share of the control transactions that landed
+func (c *ControlStream) LandingRate() float64 {
+	if c.Sent == 0 {
+		return 0
+	}
+
+	return float64(c.Landed) / float64(c.Sent)
+}
+
+// ControlComparison compares the landing rate and median landing time of the workload with the ones
+// of the control stream, a low landing rate of both points at the cluster rather than the endpoint
+func (b *Benchmark) ControlComparison() string {
+	c := b.Control
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	workloadRate := 0.0
+	if b.SentTransactions > 0 {
+		workloadRate = float64(b.ProcessedTransactions) / float64(b.SentTransactions)
+	}
+
+	out := fmt.Sprintf("%.1f%% vs %.1f%% landed", workloadRate*100.0, c.LandingRate()*100.0)
+
+	landing, ok := b.LandingTimeStats()
+	if ok && len(c.Deltas) > 0 {
+		out += fmt.Sprintf(", median %s vs %s", landing.Median.Truncate(time.Millisecond), ComputeLandingStats(c.Deltas).Median.Truncate(time.Millisecond))
+	}
+
+	return out
+}
+
+// Result returns the results of the control stream
+func (c *ControlStream) Result() *ControlResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return &ControlResult{
+		Sent:             c.Sent,
+		Landed:           c.Landed,
+		LandingRate:      c.LandingRate(),
+		ComputeUnitPrice: GlobalConfig.ControlCuPrice,
+		LandingTimes:     NewLandingTimesResult(c.Deltas),
+	}
+}
@@ -0,0 +1,165 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// CrossCheckListener records the landings notified by a second websocket, independent of the endpoint,
+// they're compared with the landings of the run once it's over, to spot the notifications missed by either
+type CrossCheckListener struct {
+	Url           string
+	Client        *ws.Client
+	Subscriptions []*ws.LogSubscription
+
+	// the signatures notified, whether they belong to the run or not
+	Seen map[solana.Signature]bool
+
+	// set if the connection was lost during the run, in which case the cross-check is incomplete
+	Lost bool
+
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func NewCrossCheckListener(url string) *CrossCheckListener {
+	return &CrossCheckListener{
+		Url:     url,
+		Seen:    make(map[solana.Signature]bool),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Connect subscribes to the logs of every test wallet, like the websocket listener of the endpoint
+func (c *CrossCheckListener) Connect(ctx context.Context) error {
+	wsClient, err := ConnectWs(ctx, c.Url)
+	if err != nil {
+		return fmt.Errorf("error connecting to cross_check_ws_url: %w", err)
+	}
+
+	for _, wallet := range TestAccounts {
+		sub, err := wsClient.LogsSubscribeMentions(wallet.PublicKey(), GlobalConfig.GetCommitment())
+		if err != nil {
+			wsClient.Close()
+			return fmt.Errorf("error subscribing to logs on cross_check_ws_url: %w", err)
+		}
+		c.Subscriptions = append(c.Subscriptions, sub)
+	}
+
+	c.Client = wsClient
+	return nil
+}
+
+func (c *CrossCheckListener) Start() {
+	for _, sub := range c.Subscriptions {
+		c.wg.Add(1)
+		go c.listen(sub)
+	}
+}
+
+// listen records the signatures of the successful transactions notified by the subscription,
+// the connection isn't restored once lost
+func (c *CrossCheckListener) listen(sub *ws.LogSubscription) {
+	defer c.wg.Done()
+
+	for {
+		got, err := sub.Recv()
+		if err != nil {
+			select {
+			case <-c.stopped:
+			default:
+				log.Warn("Cross-check websocket connection lost, the cross-check will be incomplete", "err", err)
+
+				c.mu.Lock()
+				c.Lost = true
+				c.mu.Unlock()
+			}
+			return
+		}
+
+		if got == nil || got.Value.Err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		c.Seen[got.Value.Signature] = true
+		c.mu.Unlock()
+	}
+}
+
+func (c *CrossCheckListener) Stop() {
+	c.stopOnce.Do(func() { close(c.stopped) })
+
+	for _, sub := range c.Subscriptions {
+		sub.Unsubscribe()
+	}
+	c.wg.Wait()
+
+	c.Client.Close()
+}
+
+// CrossCheckStats returns the number of transactions of the run notified by the cross-check websocket,
+// and the signatures of the landed transactions it missed, and of the ones it saw but the run didn't record, in send order
+func (b *Benchmark) CrossCheckStats() (seen int, missedByCrossCheck []solana.Signature, missedByEndpoint []solana.Signature) {
+	b.CrossCheck.mu.Lock()
+	defer b.CrossCheck.mu.Unlock()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	records := []*TxRecord{}
+	for _, record := range b.TxRecords {
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Num < records[j].Num
+	})
+
+	for _, record := range records {
+		crossChecked := b.CrossCheck.Seen[record.Signature]
+		if crossChecked {
+			seen++
+		}
+
+		switch {
+		case record.Landed && !crossChecked:
+			missedByCrossCheck = append(missedByCrossCheck, record.Signature)
+		case !record.Landed && crossChecked:
+			missedByEndpoint = append(missedByEndpoint, record.Signature)
+		}
+	}
+
+	return seen, missedByCrossCheck, missedByEndpoint
+}
+
+// DisplayCrossCheck logs how the landings of the run compare with the ones of the cross-check websocket
+func (b *Benchmark) DisplayCrossCheck() {
+	seen, missedByCrossCheck, missedByEndpoint := b.CrossCheckStats()
+
+	SimpleLogger.Printf("Cross-Check WS URL     : %s", RedactUrl(b.CrossCheck.Url))
+	SimpleLogger.Printf("Cross-Check Landed     : %d/%d", seen, b.SentTransactions)
+	SimpleLogger.Printf("Missed by Cross-Check  : %d", len(missedByCrossCheck))
+	SimpleLogger.Printf("Missed by Endpoint     : %d", len(missedByEndpoint))
+	if b.CrossCheck.Lost {
+		SimpleLogger.Printf("Cross-Check Lost       : the connection was lost during the run, the cross-check is incomplete")
+	}
+
+	// the transactions the endpoint under-reported, avoid flooding the console
+	for i, sig := range missedByEndpoint {
+		if i >= MaxDisplayedDropped {
+			SimpleLogger.Printf("  ... and %d more, see %s", len(missedByEndpoint)-i, ResultsFileName)
+			break
+		}
+		SimpleLogger.Printf("  %s", sig)
+	}
+	SimpleLogger.Printf("")
+}
@@ -0,0 +1,97 @@
+package bench
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// categories of the errors the transactions failed to be sent with
+const (
+	SendErrorRateLimit         = "rate_limit"
+	SendErrorTimeout           = "timeout"
+	SendErrorBlockhashNotFound = "blockhash_not_found"
+	SendErrorNodeUnhealthy     = "node_unhealthy"
+	SendErrorPreflight         = "preflight_failure"
+	SendErrorAlreadyProcessed  = "already_processed"
+	SendErrorHttp              = "http_error"
+	SendErrorRpc               = "rpc_error"
+	SendErrorOther             = "other"
+)
+
+// CategorizeSendError returns the category of the error a transaction failed to be sent with,
+// the messages are checked along with the codes since the RPCs don't all use the same codes
+func CategorizeSendError(err error) string {
+	if IsRateLimitError(err) {
+		return SendErrorRateLimit
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return SendErrorTimeout
+	}
+
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		message := strings.ToLower(rpcErr.Message)
+
+		switch {
+		case strings.Contains(message, "blockhash not found"):
+			return SendErrorBlockhashNotFound
+		case rpcErr.Code == NodeUnhealthyCode || strings.Contains(message, "unhealthy") || strings.Contains(message, "behind"):
+			return SendErrorNodeUnhealthy
+		case strings.Contains(message, "already been processed"):
+			return SendErrorAlreadyProcessed
+		case rpcErr.Code == PreflightFailureCode:
+			return SendErrorPreflight
+		default:
+			return SendErrorRpc
+		}
+	}
+
+	var httpErr *jsonrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return SendErrorHttp
+	}
+
+	return SendErrorOther
+}
+
+// RecordSendError counts a transaction that failed to be sent under the category of its error
+func (b *Benchmark) RecordSendError(err error) {
+	category := CategorizeSendError(err)
+
+	b.mu.Lock()
+	b.SendErrors[category] += 1
+	b.mu.Unlock()
+
+	SendErrorCounter.WithLabelValues(b.TestID, b.Endpoint.GetLabel(), category).Inc()
+}
+
+// DisplayErrorBreakdown logs the number of transactions that failed to be sent per error category,
+// the most frequent first
+func (b *Benchmark) DisplayErrorBreakdown() {
+	categories := []string{}
+	var total uint64
+	for category, count := range b.SendErrors {
+		categories = append(categories, category)
+		total += count
+	}
+
+	slices.SortFunc(categories, func(a, c string) int {
+		if n := cmp.Compare(b.SendErrors[c], b.SendErrors[a]); n != 0 {
+			return n
+		}
+		return strings.Compare(a, c)
+	})
+
+	SimpleLogger.Printf("Error Breakdown        : %d txs not sent", total)
+	for _, category := range categories {
+		count := b.SendErrors[category]
+		SimpleLogger.Printf("  %-20s : %d (%.1f%%)", category, count, float64(count)/float64(total)*100.0)
+	}
+	SimpleLogger.Printf("")
+}
@@ -0,0 +1,105 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+)
+
+// types of the events written to the events file
+const (
+	EventSent   = "sent"
+	EventLanded = "landed"
+)
+
+// Event is a line of the events file
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	TestID    string    `json:"test_id"`
+	Endpoint  string    `json:"endpoint"`
+	Run       uint64    `json:"run"`
+	Signature string    `json:"signature"`
+	Num       uint64    `json:"num"`
+
+	// only set for the landings
+	Slot    uint64   `json:"slot,omitempty"`
+	DeltaMs *float64 `json:"delta_ms,omitempty"`
+
+	// set for the landings found by a status sweep, their time is when they were found
+	Backfilled bool `json:"backfilled,omitempty"`
+}
+
+var (
+	// the events file, only open with events_file
+	EventsFile *os.File
+
+	eventsMu      sync.Mutex
+	eventsEncoder *json.Encoder
+)
+
+// OpenEventsFile creates the events file next to the log file
+func OpenEventsFile() error {
+	file, err := os.OpenFile(EventsFileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("error opening events file: %w", err)
+	}
+
+	EventsFile = file
+	eventsEncoder = json.NewEncoder(file)
+
+	return nil
+}
+
+// WriteEvent appends the event of a transaction to the events file, if open,
+// each event is written at once so that the file can be followed during the run
+func (b *Benchmark) WriteEvent(event Event) {
+	if EventsFile == nil {
+		return
+	}
+
+	event.Time = event.Time.UTC()
+	event.TestID = b.TestID
+	event.Endpoint = b.Endpoint.GetLabel()
+	event.Run = b.RunNumber
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	if err := eventsEncoder.Encode(event); err != nil {
+		log.Warn("Unable to write to the events file", "err", err)
+	}
+}
+
+// WriteSentEvent writes the event of a sent transaction
+func (b *Benchmark) WriteSentEvent(sig solana.Signature, num uint64, sendTime time.Time) {
+	b.WriteEvent(Event{Time: sendTime, Type: EventSent, Signature: sig.String(), Num: num})
+}
+
+// WriteLandedEvent writes the event of a landed transaction, the delta is omitted if unknown
+func (b *Benchmark) WriteLandedEvent(record *TxRecord) {
+	event := Event{
+		Time:       record.LandTime,
+		Type:       EventLanded,
+		Signature:  record.Signature.String(),
+		Num:        record.Num,
+		Slot:       record.Slot,
+		Backfilled: record.Backfilled,
+	}
+
+	if record.HasLandingTime() {
+		delta := durationToMs(record.Delta)
+		event.DeltaMs = &delta
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.WriteEvent(event)
+}
@@ -0,0 +1,71 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/montanaflynn/stats"
+)
+
+// RecentFeePercentile returns the configured percentile of the recent prioritization fees of the cluster,
+// in micro-lamports per compute unit, along with the number of slots they span, 0 if none is available
+func RecentFeePercentile(ctx context.Context, rpcClient *rpc.Client) (uint64, int, error) {
+	fees, err := rpcClient.GetRecentPrioritizationFees(ctx, solana.PublicKeySlice{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("error getting recent prioritization fees: %w", err)
+	}
+
+	if len(fees) == 0 {
+		return 0, 0, nil
+	}
+
+	// the fees are in micro-lamports per compute unit
+	var microLamports []float64
+	for _, fee := range fees {
+		microLamports = append(microLamports, float64(fee.PrioritizationFee))
+	}
+
+	percentile, err := stats.Percentile(microLamports, GlobalConfig.GetPrioFeePercentile())
+	if err != nil {
+		return 0, 0, fmt.Errorf("error computing priority fee percentile: %w", err)
+	}
+
+	return uint64(math.Ceil(percentile)), len(fees), nil
+}
+
+// SuggestPriorityFee logs the compute unit price paid by the recent transactions of the cluster
+// when no priority fee is set, and uses it for the test with apply_suggested_fee
+func SuggestPriorityFee(ctx context.Context) {
+	if GlobalConfig.GetComputeUnitPrice() != 0 || GlobalConfig.GetPrioFeeMode() != PrioFeeModeStatic || GlobalConfig.IsFeeSweep() {
+		return
+	}
+
+	price, slots, err := RecentFeePercentile(ctx, NewRpcClient(GlobalConfig.GetEndpoints()[0].RpcUrl))
+	if err != nil {
+		log.Warn("Unable to suggest a priority fee", "err", err)
+		return
+	}
+
+	if slots == 0 {
+		log.Warn("Unable to suggest a priority fee, no recent prioritization fees available")
+		return
+	}
+
+	keyvals := []interface{}{
+		"percentile", GlobalConfig.GetPrioFeePercentile(),
+		"slots", slots,
+		"cu_price", FormatComputeUnitPrice(price),
+	}
+
+	if !GlobalConfig.ApplySuggestedFee {
+		log.Info("No priority fee set, suggested compute_unit_price_microlamports from the recent fees", keyvals...)
+		return
+	}
+
+	GlobalConfig.ComputeUnitPrice = price
+	log.Info("No priority fee set, applied the suggested compute unit price from the recent fees", keyvals...)
+}
@@ -0,0 +1,122 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// the genesis hash of devnet, whose faucet funds the test wallets
+	DevnetGenesisHash = "EtWTRABZaYq6iMfeYKouRu166VU2xqa1wcaWoxPkrZBG"
+
+	// the genesis hash of mainnet, where no airdrop is ever requested
+	MainnetGenesisHash = "5eykt4UsFv8P8NJdTREpY1vzqKqZKvdpKuc147dw2N9d"
+
+	// the devnet faucet rejects larger airdrops, a local test validator has no limit
+	MaxDevnetAirdropLamports = 1 * solana.LAMPORTS_PER_SOL
+
+	// time to wait for an airdrop to be confirmed
+	AirdropTimeout = 30 * time.Second
+)
+
+// IsLocalUrl reports whether the url points at the local machine, e.g. a solana-test-validator
+func IsLocalUrl(rawUrl string) bool {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+
+	if u.Hostname() == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && ip.IsLoopback()
+}
+
+// AutoFund airdrops the missing balance to each test wallet short of the estimated cost of the test,
+// it returns an error unless the rpc url is on devnet or a local test validator
+func AutoFund(ctx context.Context) error {
+	rpcUrl := GlobalConfig.GetEndpoints()[0].RpcUrl
+	rpcClient := NewRpcClient(rpcUrl)
+
+	genesisHash, err := rpcClient.GetGenesisHash(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting the genesis hash of rpc_url %s: %v", RedactUrl(rpcUrl), err)
+	}
+
+	devnet := genesisHash.String() == DevnetGenesisHash
+	switch {
+	case genesisHash.String() == MainnetGenesisHash:
+		return errors.New("auto_fund can't airdrop on mainnet, fund the test wallets instead")
+	case !devnet && !IsLocalUrl(rpcUrl):
+		return fmt.Errorf("auto_fund is only supported on devnet and local test validators, rpc_url %s is neither", RedactUrl(rpcUrl))
+	}
+
+	totalCost := EstimateTestCost()
+
+	for _, wallet := range TestAccounts {
+		balance, err := rpcClient.GetBalance(ctx, wallet.PublicKey(), rpc.CommitmentConfirmed)
+		if err != nil || balance == nil {
+			return fmt.Errorf("error getting test wallet balance: %v", err)
+		}
+
+		if balance.Value >= totalCost {
+			continue
+		}
+
+		lamports := totalCost - balance.Value
+		if devnet {
+			lamports = min(lamports, MaxDevnetAirdropLamports)
+		}
+
+		log.Info("Requesting an airdrop to the test wallet", "wallet", wallet.PublicKey(), "amount", fmt.Sprintf("%.6f SOL", float64(lamports)/float64(solana.LAMPORTS_PER_SOL)))
+
+		if err := Airdrop(ctx, rpcClient, wallet.PublicKey(), lamports); err != nil {
+			return fmt.Errorf("error airdropping to test wallet %s: %v", wallet.PublicKey(), err)
+		}
+	}
+
+	return nil
+}
+
+// Airdrop requests an airdrop to the wallet, and waits until it's confirmed
+func Airdrop(ctx context.Context, rpcClient *rpc.Client, wallet solana.PublicKey, lamports uint64) error {
+	ctx, cancel := context.WithTimeout(ctx, AirdropTimeout)
+	defer cancel()
+
+	sig, err := rpcClient.RequestAirdrop(ctx, wallet, lamports, rpc.CommitmentConfirmed)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("Airdrop requested", "sig", sig)
+
+	for {
+		statuses, err := rpcClient.GetSignatureStatuses(ctx, false, sig)
+		if err == nil && len(statuses.Value) > 0 && statuses.Value[0] != nil {
+			status := statuses.Value[0]
+			if status.Err != nil {
+				return fmt.Errorf("airdrop failed: %v", status.Err)
+			}
+
+			if CommitmentReached(status.ConfirmationStatus, rpc.CommitmentConfirmed) {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.New("timed out waiting for the airdrop to be confirmed")
+		case <-time.After(time.Second):
+		}
+	}
+}
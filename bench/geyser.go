@@ -0,0 +1,221 @@
+package bench
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+)
+
+// GeyserListener streams the landings from a Yellowstone gRPC transactions subscription,
+// which is usually fed directly by a validator and lags less than the public websockets
+type GeyserListener struct {
+	Bench     *Benchmark
+	Conn      *grpc.ClientConn
+	Stream    pb.Geyser_SubscribeClient
+	Listening bool
+
+	// cancels the stream of the current connection
+	cancel context.CancelFunc
+}
+
+func NewGeyserListener(bench *Benchmark) *GeyserListener {
+	return &GeyserListener{Bench: bench}
+}
+
+// Connect connects to the geyser endpoint, the listener is then listening until stopped
+func (l *GeyserListener) Connect() error {
+	if err := l.connect(); err != nil {
+		return err
+	}
+
+	l.Listening = true
+	return nil
+}
+
+func (l *GeyserListener) IsListening() bool {
+	return l.Listening
+}
+
+// connect (re)connects to the geyser endpoint and subscribes to the successful
+// transactions involving any of the test wallets
+func (l *GeyserListener) connect() error {
+	// close the previous connection if any
+	if l.Conn != nil {
+		l.cancel()
+		l.Conn.Close()
+		l.Conn = nil
+	}
+
+	conn, err := DialGeyser(GlobalConfig.GeyserUrl)
+	if err != nil {
+		return fmt.Errorf("error connecting to geyser: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(l.Bench.ctx)
+	if GlobalConfig.GeyserToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-token", GlobalConfig.GeyserToken)
+	}
+
+	stream, err := pb.NewGeyserClient(conn).Subscribe(ctx)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return fmt.Errorf("error subscribing to geyser: %w", err)
+	}
+
+	accounts := []string{}
+	for _, wallet := range TestAccounts {
+		accounts = append(accounts, wallet.PublicKey().String())
+	}
+
+	vote, failed := false, false
+	commitment := GeyserCommitment(GlobalConfig.GetCommitment())
+	request := &pb.SubscribeRequest{
+		Transactions: map[string]*pb.SubscribeRequestFilterTransactions{
+			"memobench": {Vote: &vote, Failed: &failed, AccountInclude: accounts},
+		},
+		Commitment: &commitment,
+	}
+
+	if err := stream.Send(request); err != nil {
+		cancel()
+		conn.Close()
+		return fmt.Errorf("error subscribing to geyser: %w", err)
+	}
+
+	l.Conn = conn
+	l.Stream = stream
+	l.cancel = cancel
+
+	return nil
+}
+
+// Reconnect tries to restore a broken geyser stream
+func (l *GeyserListener) Reconnect() bool {
+	return l.Bench.Reconnect("geyser", l.IsListening, l.connect)
+}
+
+// Start listens for the landed transactions until stopped, the listener must be connected beforehand
+func (l *GeyserListener) Start() {
+	defer l.Bench.wg.Done()
+
+	log.Info("Listening for transactions on geyser...", "endpoint", l.Bench.Endpoint.GetLabel())
+
+	// the memos are matched with the same template used to generate them
+	re := MemoRegexp(GlobalConfig.GetMemoTemplate())
+
+	for l.Listening {
+		update, err := l.Stream.Recv()
+		if err != nil {
+			// the stream is canceled when the listener is stopped
+			if !l.Listening {
+				break
+			}
+
+			log.Error("Geyser stream lost", "err", err)
+
+			if !l.Reconnect() {
+				// in both mode, the poller keeps tracking the landings
+				log.Error("Unable to reconnect to geyser, giving up")
+				l.Stop()
+				break
+			}
+
+			if l.Bench.AllTransactionsLanded() {
+				l.Bench.Stop()
+			}
+			continue
+		}
+
+		// answer the pings, some load balancers close idle streams
+		if update.GetPing() != nil {
+			if err := l.Stream.Send(&pb.SubscribeRequest{Ping: &pb.SubscribeRequestPing{Id: 1}}); err != nil {
+				log.Warn("Unable to answer the geyser ping", "err", err)
+			}
+			continue
+		}
+
+		got := update.GetTransaction()
+		if got == nil || got.GetTransaction() == nil {
+			continue
+		}
+
+		info := got.GetTransaction()
+		if len(info.GetSignature()) != solana.SignatureLength {
+			continue
+		}
+
+		sig := solana.SignatureFromBytes(info.GetSignature())
+		l.Bench.HandleLogs(re, sig, got.GetSlot(), info.GetMeta().GetLogMessages())
+	}
+
+	if l.Conn != nil {
+		l.Conn.Close()
+	}
+
+	log.Info("Stopping listening for geyser transactions...")
+}
+
+func (l *GeyserListener) Stop() {
+	if !l.Listening {
+		return
+	}
+
+	l.Listening = false
+
+	if l.cancel != nil {
+		l.cancel()
+	}
+}
+
+// DialGeyser opens the grpc connection to the geyser url, using tls for https urls
+func DialGeyser(geyserUrl string) (*grpc.ClientConn, error) {
+	u, err := url.Parse(geyserUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := insecure.NewCredentials()
+	port := "80"
+	if u.Scheme == "https" {
+		creds = credentials.NewTLS(&tls.Config{})
+		port = "443"
+	}
+
+	target := u.Host
+	if u.Port() == "" {
+		target = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	return grpc.NewClient(
+		target,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: 10 * time.Second, Timeout: 5 * time.Second, PermitWithoutStream: true}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(64*1024*1024)),
+	)
+}
+
+// GeyserCommitment converts the commitment to its geyser counterpart
+func GeyserCommitment(commitment rpc.CommitmentType) pb.CommitmentLevel {
+	switch commitment {
+	case rpc.CommitmentConfirmed:
+		return pb.CommitmentLevel_CONFIRMED
+	case rpc.CommitmentFinalized:
+		return pb.CommitmentLevel_FINALIZED
+	default:
+		return pb.CommitmentLevel_PROCESSED
+	}
+}
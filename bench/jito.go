@@ -0,0 +1,76 @@
+package bench
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// JitoClient submits transactions as bundles to a Jito block engine
+type JitoClient struct {
+	Client jsonrpc.RPCClient
+
+	// the accounts the tips can be paid to, fetched from the block engine
+	TipAccounts []solana.PublicKey
+}
+
+func NewJitoClient(url string) *JitoClient {
+	return &JitoClient{Client: NewJsonRpcClient(url, nil)}
+}
+
+// FetchTipAccounts loads the tip accounts of the block engine
+func (j *JitoClient) FetchTipAccounts(ctx context.Context) error {
+	var out []string
+	if err := j.Client.CallForInto(ctx, &out, "getTipAccounts", nil); err != nil {
+		return fmt.Errorf("error getting jito tip accounts: %w", err)
+	}
+
+	if len(out) == 0 {
+		return fmt.Errorf("error getting jito tip accounts: no tip account returned")
+	}
+
+	j.TipAccounts = []solana.PublicKey{}
+	for _, account := range out {
+		key, err := solana.PublicKeyFromBase58(account)
+		if err != nil {
+			return fmt.Errorf("error parsing jito tip account %q: %w", account, err)
+		}
+		j.TipAccounts = append(j.TipAccounts, key)
+	}
+
+	return nil
+}
+
+// TipInstruction builds the transfer of the tip from the wallet to a random tip account,
+// picking a random one reduces the write lock contention on the tip accounts
+func (j *JitoClient) TipInstruction(lamports uint64, wallet solana.PublicKey) solana.Instruction {
+	tipAccount := j.TipAccounts[rand.Intn(len(j.TipAccounts))]
+
+	return system.NewTransferInstruction(lamports, wallet, tipAccount).Build()
+}
+
+// SendBundle submits the transactions as a single bundle,
+// it returns the bundle id once the block engine accepted it
+func (j *JitoClient) SendBundle(ctx context.Context, txs ...*solana.Transaction) (string, error) {
+	encoded := []string{}
+	for _, tx := range txs {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return "", fmt.Errorf("error encoding tx: %w", err)
+		}
+		encoded = append(encoded, base64.StdEncoding.EncodeToString(data))
+	}
+
+	var bundleId string
+	params := []interface{}{encoded, map[string]string{"encoding": "base64"}}
+	if err := j.Client.CallForInto(ctx, &bundleId, "sendBundle", params); err != nil {
+		return "", err
+	}
+
+	return bundleId, nil
+}
@@ -0,0 +1,163 @@
+package bench
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// LeaderboardEntry is the standing of an endpoint, across its runs
+type LeaderboardEntry struct {
+	Rank  int    `json:"rank"`
+	Label string `json:"label"`
+	Runs  int    `json:"runs"`
+
+	LandingRate       float64 `json:"landing_rate"`
+	LandingRateStdDev float64 `json:"landing_rate_stddev"`
+
+	// the mean of the median landing times of the runs, omitted if no transaction landed
+	Median       *float64 `json:"median_ms,omitempty"`
+	MedianStdDev float64  `json:"median_ms_stddev"`
+
+	// set when the results are the same as the previous entry, which then shares its rank
+	Tied bool `json:"tied,omitempty"`
+
+	// set when the difference with the previous entry is within the standard deviations across the runs
+	WithinNoise bool `json:"within_noise,omitempty"`
+}
+
+// BuildLeaderboard ranks the endpoints by landing rate, then by median landing time
+func BuildLeaderboard(aggregates []*Aggregate) []LeaderboardEntry {
+	out := []LeaderboardEntry{}
+	for _, aggregate := range aggregates {
+		entry := LeaderboardEntry{Label: aggregate.Label, Runs: aggregate.Runs}
+		entry.LandingRate, entry.LandingRateStdDev = Spread(aggregate.LandingRates)
+
+		if len(aggregate.Medians) > 0 {
+			median, stdDev := Spread(aggregate.Medians)
+			entry.Median = &median
+			entry.MedianStdDev = stdDev
+		}
+
+		out = append(out, entry)
+	}
+
+	// the rates are compared to the displayed precision, so that the ties look like ties,
+	// the endpoints without any landing go last
+	slices.SortStableFunc(out, func(a, b LeaderboardEntry) int {
+		if n := cmp.Compare(roundRate(b.LandingRate), roundRate(a.LandingRate)); n != 0 {
+			return n
+		}
+
+		switch {
+		case a.Median == nil && b.Median == nil:
+			return 0
+		case a.Median == nil:
+			return 1
+		case b.Median == nil:
+			return -1
+		}
+
+		return cmp.Compare(math.Round(*a.Median), math.Round(*b.Median))
+	})
+
+	for i := range out {
+		out[i].Rank = i + 1
+		if i == 0 {
+			continue
+		}
+
+		prev, entry := out[i-1], &out[i]
+		if sameStanding(prev, *entry) {
+			entry.Tied = true
+			entry.Rank = prev.Rank
+			continue
+		}
+
+		entry.WithinNoise = withinNoise(prev, *entry)
+	}
+
+	return out
+}
+
+// roundRate rounds the landing rate to a tenth of a percent
+func roundRate(rate float64) float64 {
+	return math.Round(rate * 1000)
+}
+
+// sameStanding reports whether the two entries have the same landing rate and median, as displayed
+func sameStanding(a, b LeaderboardEntry) bool {
+	if roundRate(a.LandingRate) != roundRate(b.LandingRate) {
+		return false
+	}
+
+	if a.Median == nil || b.Median == nil {
+		return a.Median == nil && b.Median == nil
+	}
+
+	return math.Round(*a.Median) == math.Round(*b.Median)
+}
+
+// withinNoise reports whether the differences of landing rate and median between the two entries
+// are within their combined standard deviation, which is only known when the test is repeated
+func withinNoise(a, b LeaderboardEntry) bool {
+	if a.Runs < 2 || b.Runs < 2 {
+		return false
+	}
+
+	if math.Abs(a.LandingRate-b.LandingRate) > math.Hypot(a.LandingRateStdDev, b.LandingRateStdDev) {
+		return false
+	}
+
+	if a.Median == nil || b.Median == nil {
+		return a.Median == nil && b.Median == nil
+	}
+
+	return math.Abs(*a.Median-*b.Median) <= math.Hypot(a.MedianStdDev, b.MedianStdDev)
+}
+
+// DisplayLeaderboard logs the ranked endpoints, and highlights the winner if there's a clear one
+func DisplayLeaderboard(leaderboard []LeaderboardEntry) {
+	width := len("Endpoint")
+	for _, entry := range leaderboard {
+		width = max(width, len(entry.Label))
+	}
+
+	SimpleLogger.Printf("")
+	SimpleLogger.Printf("Leaderboard            : by landing rate, then median landing time")
+	SimpleLogger.Printf("%4s | %-*s | %-17s | %-19s | %s", "Rank", width, "Endpoint", "Landing Rate", "Median", "Note")
+	SimpleLogger.Printf("%s", strings.Repeat("-", width+60))
+
+	for i, entry := range leaderboard {
+		rate := fmt.Sprintf("%.1f%%", entry.LandingRate*100)
+		if entry.Runs > 1 {
+			rate += fmt.Sprintf(" (± %.1f%%)", entry.LandingRateStdDev*100)
+		}
+
+		median := "-"
+		if entry.Median != nil {
+			median = msToDuration(*entry.Median).String()
+			if entry.Runs > 1 {
+				median += fmt.Sprintf(" (± %s)", msToDuration(entry.MedianStdDev))
+			}
+		}
+
+		note := ""
+		switch {
+		case entry.Tied:
+			note = fmt.Sprintf("tied with #%d", entry.Rank)
+		case entry.WithinNoise:
+			note = fmt.Sprintf("within noise of #%d", leaderboard[i-1].Rank)
+		case i == 0 && len(leaderboard) > 1 && !leaderboard[1].Tied && !leaderboard[1].WithinNoise:
+			note = "WINNER"
+		}
+
+		SimpleLogger.Printf("%4d | %-*s | %-17s | %-19s | %s", entry.Rank, width, entry.Label, rate, median, note)
+	}
+
+	if len(leaderboard) > 1 && (leaderboard[1].Tied || leaderboard[1].WithinNoise) {
+		SimpleLogger.Printf("No clear winner, the top endpoints are tied or within the noise")
+	}
+}
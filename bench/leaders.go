@@ -0,0 +1,107 @@
+package bench
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+)
+
+// leader stats of the slots spanned by the landings
+type leaderStats struct {
+	Leader solana.PublicKey
+	Slots  int
+	Blocks int
+	Txs    uint64
+}
+
+// FetchSlotLeaders fetches the leaders of the slots spanned by the landings, in as few
+// getSlotLeaders requests as possible, the blocks are then annotated without any further request
+func (b *Benchmark) FetchSlotLeaders(ctx context.Context) {
+	b.mu.RLock()
+	slots := []uint64{}
+	for slot := range b.TxBlocks {
+		slots = append(slots, slot)
+	}
+	b.mu.RUnlock()
+
+	if len(slots) == 0 {
+		return
+	}
+
+	first, last := slices.Min(slots), slices.Max(slots)
+	rpcClient := NewRpcClient(b.Endpoint.RpcUrl)
+
+	leaders := make(map[uint64]solana.PublicKey)
+	for start := first; start <= last; start += MaxSlotLeaders {
+		// the schedule is only available for the recent slots, the blocks are then left as is
+		out, err := rpcClient.GetSlotLeaders(ctx, start, min(MaxSlotLeaders, last-start+1))
+		if err != nil {
+			log.Warn("Unable to get the slot leaders, the blocks will not be annotated", "err", err)
+			return
+		}
+
+		for i, leader := range out {
+			leaders[start+uint64(i)] = leader
+		}
+	}
+
+	b.mu.Lock()
+	b.SlotLeaders = leaders
+	b.mu.Unlock()
+}
+
+// SlotLeaderLabel returns the shortened leader of the slot to display along with its block, empty if unknown
+func (b *Benchmark) SlotLeaderLabel(slot uint64) string {
+	leader, ok := b.SlotLeaders[slot]
+	if !ok {
+		return ""
+	}
+
+	key := leader.String()
+	return fmt.Sprintf("%s..%s | ", key[:4], key[len(key)-4:])
+}
+
+// LeaderStats returns the number of slots of each leader in the range spanned by the landings,
+// how many of them contained a landing, and the number of transactions landed, the most landings first
+func (b *Benchmark) LeaderStats() []*leaderStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	byLeader := make(map[solana.PublicKey]*leaderStats)
+	out := []*leaderStats{}
+	for slot, leader := range b.SlotLeaders {
+		stats, ok := byLeader[leader]
+		if !ok {
+			stats = &leaderStats{Leader: leader}
+			byLeader[leader] = stats
+			out = append(out, stats)
+		}
+
+		stats.Slots += 1
+		if count, ok := b.TxBlocks[slot]; ok {
+			stats.Blocks += 1
+			stats.Txs += count
+		}
+	}
+
+	slices.SortFunc(out, func(a, c *leaderStats) int {
+		if n := cmp.Compare(c.Txs, a.Txs); n != 0 {
+			return n
+		}
+		return cmp.Compare(a.Leader.String(), c.Leader.String())
+	})
+
+	return out
+}
+
+// DisplayLeaders logs the landings of each leader of the slots spanned by the landings
+func (b *Benchmark) DisplayLeaders() {
+	SimpleLogger.Printf("")
+	for _, stats := range b.LeaderStats() {
+		SimpleLogger.Printf("Leader %-44s : %3d slots | %3d with landings | %4d txs", stats.Leader, stats.Slots, stats.Blocks, stats.Txs)
+	}
+}
@@ -0,0 +1,150 @@
+package bench
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// the commitment levels followed by the lifecycle tracker, in order
+var LifecycleCommitments = []rpc.CommitmentType{rpc.CommitmentProcessed, rpc.CommitmentConfirmed, rpc.CommitmentFinalized}
+
+// LifecycleTracker polls the signature statuses of the sent transactions,
+// to record the time each one took to reach every commitment level
+type LifecycleTracker struct {
+	Bench  *Benchmark
+	Client *rpc.Client
+}
+
+func NewLifecycleTracker(bench *Benchmark) *LifecycleTracker {
+	return &LifecycleTracker{
+		Bench:  bench,
+		Client: NewRpcClient(bench.Endpoint.RpcUrl),
+	}
+}
+
+// Start polls the statuses until the run is stopped, then keeps polling
+// until the landed transactions are finalized, or the finalization timeout elapses
+func (t *LifecycleTracker) Start() {
+	defer t.Bench.wg.Done()
+
+	log.Info("Tracking the commitment levels...", "endpoint", t.Bench.Endpoint.GetLabel(), "interval", LifecyclePollInterval)
+
+	ticker := time.NewTicker(LifecyclePollInterval)
+	defer ticker.Stop()
+
+	stopped := t.Bench.stopped
+	var timeout <-chan time.Time
+
+	for {
+		select {
+		case <-stopped:
+			// the run only waited for the configured commitment level
+			stopped = nil
+			timeout = time.After(FinalizationTimeout)
+
+			log.Info("Waiting for the landed transactions to be finalized...", "timeout", FinalizationTimeout)
+		case <-timeout:
+			log.Warn("Timed out waiting for the landed transactions to be finalized", "pending", t.Pending(true))
+			return
+		case <-ticker.C:
+			// after the run, only the landed transactions can still be finalized
+			pending := t.Pending(stopped == nil)
+			if stopped == nil && len(pending) == 0 {
+				return
+			}
+
+			t.Poll(pending)
+		}
+	}
+}
+
+// Pending returns the transactions that haven't been seen finalized yet,
+// if landedOnly is set, the ones that haven't been seen landed are skipped
+func (t *LifecycleTracker) Pending(landedOnly bool) []solana.Signature {
+	t.Bench.mu.RLock()
+	defer t.Bench.mu.RUnlock()
+
+	out := []solana.Signature{}
+	for sig, record := range t.Bench.TxRecords {
+		if _, ok := record.CommitmentDeltas[rpc.CommitmentFinalized]; ok {
+			continue
+		}
+
+		if landedOnly && !record.Landed && len(record.CommitmentDeltas) == 0 {
+			continue
+		}
+
+		out = append(out, sig)
+	}
+
+	return out
+}
+
+// Poll records the commitment levels newly reached by the transactions,
+// the levels skipped between two polls are recorded along with the reached one
+func (t *LifecycleTracker) Poll(pending []solana.Signature) {
+	for start := 0; start < len(pending); start += MaxSignatureStatuses {
+		batch := pending[start:min(start+MaxSignatureStatuses, len(pending))]
+
+		out, err := t.Client.GetSignatureStatuses(t.Bench.testCtx, false, batch...)
+		if err != nil {
+			log.Errorf("error getting signature statuses: %v", err)
+			continue
+		}
+		now := time.Now()
+
+		t.Bench.mu.Lock()
+		for i, status := range out.Value {
+			if status == nil || status.Err != nil {
+				continue
+			}
+
+			// the record may have been streamed out meanwhile
+			record, ok := t.Bench.TxRecords[batch[i]]
+			if !ok {
+				continue
+			}
+
+			for _, commitment := range LifecycleCommitments {
+				if _, ok := record.CommitmentDeltas[commitment]; ok || !CommitmentReached(status.ConfirmationStatus, commitment) {
+					continue
+				}
+
+				delta := now.Sub(record.SendTime)
+				record.CommitmentDeltas[commitment] = delta
+				t.Bench.CommitmentDeltas[commitment] = append(t.Bench.CommitmentDeltas[commitment], delta)
+
+				log.Debug("Tx commitment reached", "sig", batch[i].String(), "commitment", commitment, "delta", delta.Truncate(time.Millisecond).String())
+			}
+
+			t.Bench.streamRecord(record)
+		}
+		t.Bench.mu.Unlock()
+	}
+}
+
+// DisplayLifecycle logs the time the transactions took to reach each commitment level
+func (b *Benchmark) DisplayLifecycle() {
+	for _, commitment := range LifecycleCommitments {
+		deltas := b.CommitmentDeltas[commitment]
+		if len(deltas) == 0 {
+			continue
+		}
+
+		name := strings.ToUpper(string(commitment[:1])) + string(commitment[1:])
+		landing := ComputeLandingStats(deltas)
+
+		SimpleLogger.Printf("%-22s : %d/%d", name+" Txs", len(deltas), b.SentTransactions)
+		SimpleLogger.Printf("%-22s : %s", "Min To "+name, landing.Min.Truncate(time.Millisecond))
+		SimpleLogger.Printf("%-22s : %s", "Median To "+name, landing.Median.Truncate(time.Millisecond))
+		SimpleLogger.Printf("%-22s : %s", "P90 To "+name, landing.P90.Truncate(time.Millisecond))
+		SimpleLogger.Printf("%-22s : %s", "P95 To "+name, landing.P95.Truncate(time.Millisecond))
+		SimpleLogger.Printf("%-22s : %s", "P99 To "+name, landing.P99.Truncate(time.Millisecond))
+		SimpleLogger.Printf("%-22s : %s", "Max To "+name, landing.Max.Truncate(time.Millisecond))
+		SimpleLogger.Printf("")
+	}
+}
@@ -0,0 +1,331 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+var errListenerStopped = errors.New("listener stopped")
+
+// Listener streams the landings of the test transactions, it's connected
+// before the transactions are sent, then started until stopped
+type Listener interface {
+	Connect() error
+	Start()
+	Stop()
+	IsListening() bool
+}
+
+// NewListener creates the listener of the configured confirm source
+func NewListener(bench *Benchmark) Listener {
+	switch GlobalConfig.GetConfirmSource() {
+	case ConfirmSourceGeyser:
+		return NewGeyserListener(bench)
+	case ConfirmSourceBlock:
+		return NewBlockListener(bench)
+	case ConfirmSourceSignature:
+		return NewSignatureListener(bench)
+	default:
+		return NewWebsocketListener(bench)
+	}
+}
+
+// a log notification, or the error of the subscription it was received on
+type logEvent struct {
+	got *ws.LogResult
+	err error
+}
+
+type WebsocketListener struct {
+	Bench         *Benchmark
+	Client        *ws.Client
+	Subscriptions []*ws.LogSubscription
+	Listening     bool
+
+	// the notifications of all the subscriptions of the current connection,
+	// and a channel closed when the connection is replaced
+	events chan logEvent
+	closed chan struct{}
+
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func NewWebsocketListener(bench *Benchmark) *WebsocketListener {
+	return &WebsocketListener{
+		Bench:   bench,
+		stopped: make(chan struct{}),
+	}
+}
+
+// Connect connects to the websocket, the listener is then listening until stopped
+func (l *WebsocketListener) Connect() error {
+	if err := l.connect(); err != nil {
+		return err
+	}
+
+	l.Listening = true
+	return nil
+}
+
+func (l *WebsocketListener) IsListening() bool {
+	return l.Listening
+}
+
+// connect (re)connects to the websocket and subscribes to the logs of every test wallet,
+// since a logs subscription can only mention a single account
+func (l *WebsocketListener) connect() error {
+	// close the previous connection if any
+	if l.Client != nil {
+		close(l.closed)
+		l.Client.Close()
+		l.Client = nil
+	}
+
+	wsClient, err := ConnectWs(l.Bench.ctx, l.Bench.Endpoint.GetWsUrl())
+	if err != nil {
+		return fmt.Errorf("error connecting to websocket: %w", err)
+	}
+
+	subs := []*ws.LogSubscription{}
+	for _, wallet := range TestAccounts {
+		sub, err := wsClient.LogsSubscribeMentions(wallet.PublicKey(), GlobalConfig.GetCommitment())
+		if err != nil {
+			wsClient.Close()
+			return fmt.Errorf("error subscribing to logs: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	l.Client = wsClient
+	l.Subscriptions = subs
+	l.events = make(chan logEvent)
+	l.closed = make(chan struct{})
+
+	for _, sub := range subs {
+		go l.forward(sub, l.events, l.closed)
+	}
+
+	return nil
+}
+
+// forward passes the notifications of the subscription to the events channel,
+// until the subscription fails, the connection is replaced or the listener is stopped
+func (l *WebsocketListener) forward(sub *ws.LogSubscription, events chan<- logEvent, closed <-chan struct{}) {
+	for {
+		got, err := sub.Recv()
+
+		select {
+		case events <- logEvent{got: got, err: err}:
+		case <-closed:
+			return
+		case <-l.stopped:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Recv returns the next notification of any of the subscriptions
+func (l *WebsocketListener) Recv() (*ws.LogResult, error) {
+	select {
+	case event := <-l.events:
+		return event.got, event.err
+	case <-l.stopped:
+		return nil, errListenerStopped
+	}
+}
+
+// Reconnect tries to restore a broken websocket connection
+func (l *WebsocketListener) Reconnect() bool {
+	return l.Bench.Reconnect("websocket", l.IsListening, l.connect)
+}
+
+// Reconnect tries to restore a broken connection with an exponential backoff, and backfills the
+// landings missed meanwhile, it returns false if all the attempts failed or the listener was stopped
+func (b *Benchmark) Reconnect(source string, listening func() bool, connect func() error) bool {
+	delay := ReconnectBaseDelay
+
+	for attempt := 1; attempt <= MaxReconnectAttempts; attempt++ {
+		log.Warn(fmt.Sprintf("Reconnecting to %s...", source), "attempt", fmt.Sprintf("%d/%d", attempt, MaxReconnectAttempts), "delay", delay)
+		if !b.Sleep(delay) || !listening() {
+			return false
+		}
+
+		if err := connect(); err != nil {
+			log.Error(err.Error())
+			delay *= 2
+			continue
+		}
+
+		log.Info(fmt.Sprintf("Reconnected to %s", source))
+
+		// recover the transactions that landed while disconnected
+		b.BackfillLandings()
+
+		return true
+	}
+
+	return false
+}
+
+// Start listens for the landed transactions until stopped, the listener must be connected beforehand
+func (l *WebsocketListener) Start() {
+	defer l.Bench.wg.Done()
+
+	log.Info("Listening for transactions...", "endpoint", l.Bench.Endpoint.GetLabel())
+
+	// the memos are matched with the same template used to generate them
+	re := MemoRegexp(GlobalConfig.GetMemoTemplate())
+
+	for l.Listening {
+		got, err := l.Recv()
+		if err != nil {
+			// the subscription errors out when the connection is lost
+			if !l.Listening {
+				break
+			}
+
+			log.Error("Websocket connection lost", "err", err)
+
+			if !l.Reconnect() {
+				// in both mode, the poller keeps tracking the landings
+				log.Error("Unable to reconnect to websocket, giving up")
+				l.Stop()
+				break
+			}
+
+			if l.Bench.AllTransactionsLanded() {
+				l.Bench.Stop()
+			}
+			continue
+		}
+
+		if got == nil || got.Value.Err != nil {
+			continue
+		}
+
+		l.Bench.HandleLogs(re, got.Value.Signature, got.Context.Slot, got.Value.Logs)
+	}
+
+	log.Info("Stopping listening for log events...")
+}
+
+func (l *WebsocketListener) Stop() {
+	if !l.Listening {
+		return
+	}
+
+	l.Listening = false
+	l.stopOnce.Do(func() { close(l.stopped) })
+
+	for _, sub := range l.Subscriptions {
+		sub.Unsubscribe()
+	}
+}
+
+// HandleLogs records the landing of the test transaction the logs belong to, if any,
+// and stops the run once all the transactions landed, the memo is only used to spot the
+// transactions of other tests, the landings are recorded by signature
+func (b *Benchmark) HandleLogs(re *regexp.Regexp, sig solana.Signature, slot uint64, logs []string) {
+	// the other workloads have no memo, their landings are matched by signature only,
+	// as are the memos of the programs that don't log them
+	if GlobalConfig.GetWorkload() != WorkloadMemo || !GlobalConfig.LogsMemos() {
+		b.HandleLanding(sig, slot)
+		return
+	}
+
+	for _, line := range logs {
+		// ignore the warmup transactions
+		if strings.Contains(line, WarmupMemoPrefix) {
+			return
+		}
+
+		if strings.Contains(line, ControlMemoPrefix) {
+			b.HandleLanding(sig, slot)
+			return
+		}
+
+		matches := re.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		testNum, id := matches[re.SubexpIndex("num")], matches[re.SubexpIndex("id")]
+
+		if id != b.TestID {
+			log.Warn(
+				"Received unexpected test ID",
+				"num", testNum,
+				"id", id,
+				"sig", sig.String(),
+			)
+			continue
+		}
+
+		// in listen only mode, the transactions are only known once they land
+		if GlobalConfig.SplitMode == SplitModeListenOnly {
+			num, _ := strconv.ParseUint(testNum, 10, 64)
+			b.RecordListenedLanding(sig, num, slot)
+			return
+		}
+
+		if b.HandleLanding(sig, slot) {
+			return
+		}
+	}
+
+	// the logs may be truncated, or the memo logged in an unexpected format
+	if GlobalConfig.SplitMode != SplitModeListenOnly && b.HandleLanding(sig, slot) {
+		log.Debug("Tx matched by signature, its memo wasn't found in the logs", "sig", sig.String())
+	}
+}
+
+// HandleLanding records the landing of the transaction, it returns false if the transaction
+// isn't one of the run, e.g. if the test was restarted and a tx from a previous test landed,
+// or if a tx sent to a previously benchmarked endpoint landed late
+func (b *Benchmark) HandleLanding(sig solana.Signature, slot uint64) bool {
+	// the control transactions are recorded apart from the workload
+	if b.Control != nil && b.Control.RecordLanding(sig, slot) {
+		return true
+	}
+
+	// the record may be streamed out once landed
+	b.mu.RLock()
+	record := b.TxRecords[sig]
+	b.mu.RUnlock()
+
+	delta, found := b.RecordLanding(sig, slot)
+	if !found {
+		return false
+	}
+
+	b.mu.RLock()
+	landed := fmt.Sprintf("%d/%d", b.ProcessedTransactions, b.SentTransactions)
+	b.mu.RUnlock()
+
+	log.Debug(
+		"Tx Processed",
+		"num", record.Num,
+		"sig", sig.String(),
+		"delta", delta.Truncate(time.Millisecond).String(),
+		"landed", landed,
+	)
+
+	if b.AllTransactionsLanded() {
+		b.Stop()
+	}
+
+	return true
+}
@@ -0,0 +1,150 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// JsonTeeWriter receives the JSON formatted log entries of a logger,
+// writes them as is to the log file, and renders them as text on the console
+type JsonTeeWriter struct {
+	File    io.Writer
+	Console *log.Logger
+}
+
+func (w *JsonTeeWriter) Write(p []byte) (int, error) {
+	if _, err := w.File.Write(p); err != nil {
+		return 0, err
+	}
+
+	var entry map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+	if err := decoder.Decode(&entry); err != nil {
+		return 0, err
+	}
+
+	msg := entry[log.MessageKey]
+	if msg == nil {
+		msg = ""
+	}
+
+	// the console logger adds its own timestamp and prefix,
+	// the keys are sorted since the order of the original call is lost
+	keys := []string{}
+	for key := range entry {
+		switch key {
+		case log.TimestampKey, log.LevelKey, log.PrefixKey, log.MessageKey:
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	keyvals := []interface{}{}
+	for _, key := range keys {
+		keyvals = append(keyvals, key, entry[key])
+	}
+
+	// the entries of the plain loggers have no level
+	level, ok := entry[log.LevelKey].(string)
+	if !ok {
+		w.Console.Print(msg, keyvals...)
+		return len(p), nil
+	}
+
+	parsed, err := log.ParseLevel(level)
+	if err != nil {
+		parsed = log.InfoLevel
+	}
+	w.Console.Log(parsed, msg, keyvals...)
+
+	return len(p), nil
+}
+
+// SetupLogger opens the log file, in the given directory if set, which is created if needed,
+// the results files are saved next to it, with no_log_file, the logs are only written to the console
+func SetupLogger(dir string) error {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("error creating log directory: %w", err)
+		}
+	}
+
+	baseName := filepath.Join(dir, fmt.Sprintf("memobench_%d_%s", time.Now().UnixMilli(), TestID))
+	LogFileName = baseName + ".log"
+	ResultsFileName = baseName + ".json"
+	TxRecordsFileName = baseName + ".csv"
+	EventsFileName = baseName + ".ndjson"
+
+	if GlobalConfig.NoLogFile {
+		LogFileName = ""
+		ConfigureLoggers(LogFormatText)
+		return nil
+	}
+
+	logFile, err := os.OpenFile(LogFileName, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	LogFile = logFile
+
+	ConfigureLoggers(LogFormatText)
+
+	return nil
+}
+
+// ConfigureLoggers (re)creates the loggers, writing the log file in the given format
+func ConfigureLoggers(format string) {
+	// in quiet mode, only the results are shown on the console
+	var console io.Writer = os.Stdout
+	if Quiet {
+		console = io.Discard
+	}
+
+	// create a simplified logger for logging the test results
+	SimpleLogger = newLogger(format, os.Stdout, log.Options{
+		ReportTimestamp: false,
+	})
+
+	HeaderLogger = newLogger(format, console, log.Options{
+		ReportTimestamp: false,
+	})
+
+	// set the default logger for logging during the test
+	log.SetDefault(newLogger(format, console, log.Options{
+		Prefix:          TestID,
+		ReportTimestamp: true,
+		TimeFunction:    func(time.Time) time.Time { return time.Now().In(LogLocation) },
+		TimeFormat:      GlobalConfig.GetLogTimeFormat(),
+	}))
+}
+
+// newLogger creates a logger writing to both the console and the log file,
+// in json format, the entries are rendered as text on the console
+func newLogger(format string, console io.Writer, opts log.Options) *log.Logger {
+	// the format only applies to the log file
+	if LogFile == nil {
+		return log.NewWithOptions(console, opts)
+	}
+
+	if format != LogFormatJson {
+		return log.NewWithOptions(io.MultiWriter(console, LogFile), opts)
+	}
+
+	consoleLogger := log.NewWithOptions(console, opts)
+	consoleLogger.SetLevel(log.DebugLevel)
+
+	opts.Formatter = log.JSONFormatter
+	opts.TimeFormat = time.RFC3339Nano
+
+	return log.NewWithOptions(&JsonTeeWriter{File: LogFile, Console: consoleLogger}, opts)
+}
@@ -0,0 +1,81 @@
+package bench
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// placeholders of the memo template, replaced by the transaction number and the test ID
+	MemoNumPlaceholder = "{num}"
+	MemoIdPlaceholder  = "{id}"
+
+	DefaultMemoTemplate = "memobench: Test {num} [{id}]"
+)
+
+// ValidateMemoTemplate checks that the template contains each placeholder exactly once
+func ValidateMemoTemplate(template string) error {
+	for _, placeholder := range []string{MemoNumPlaceholder, MemoIdPlaceholder} {
+		if count := strings.Count(template, placeholder); count != 1 {
+			return fmt.Errorf("memo_template must contain %s exactly once, found %d", placeholder, count)
+		}
+	}
+
+	return nil
+}
+
+func FormatMemo(template string, num uint64, id string) string {
+	return strings.NewReplacer(
+		MemoNumPlaceholder, strconv.FormatUint(num, 10),
+		MemoIdPlaceholder, id,
+	).Replace(template)
+}
+
+// MemoRegexp builds the regex matching the memos generated from the template,
+// the transaction number and the test ID are captured in the "num" and "id" groups
+func MemoRegexp(template string) *regexp.Regexp {
+	// the placeholders are validated to appear exactly once
+	numIndex := strings.Index(template, MemoNumPlaceholder)
+	idIndex := strings.Index(template, MemoIdPlaceholder)
+
+	var pattern string
+	if numIndex < idIndex {
+		before, rest, _ := strings.Cut(template, MemoNumPlaceholder)
+		middle, after, _ := strings.Cut(rest, MemoIdPlaceholder)
+		pattern = memoLiteralPattern(before) + `(?P<num>\d+)` + memoLiteralPattern(middle) + `(?P<id>\w+)` + memoLiteralPattern(after)
+	} else {
+		before, rest, _ := strings.Cut(template, MemoIdPlaceholder)
+		middle, after, _ := strings.Cut(rest, MemoNumPlaceholder)
+		pattern = memoLiteralPattern(before) + `(?P<id>\w+)` + memoLiteralPattern(middle) + `(?P<num>\d+)` + memoLiteralPattern(after)
+	}
+
+	return regexp.MustCompile(pattern)
+}
+
+// memoLiteralPattern matches the literal text of the template as logged by the memo program,
+// which escapes the quotes and the backslashes, the whitespace may also be collapsed or expanded
+func memoLiteralPattern(literal string) string {
+	var pattern strings.Builder
+
+	inSpace := false
+	for _, r := range literal {
+		if unicode.IsSpace(r) {
+			if !inSpace {
+				pattern.WriteString(`\s+`)
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+
+		if r == '"' || r == '\\' {
+			pattern.WriteString(`\\?`)
+		}
+		pattern.WriteString(regexp.QuoteMeta(string(r)))
+	}
+
+	return pattern.String()
+}
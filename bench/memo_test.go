@@ -0,0 +1,183 @@
+package bench
+
+import "testing"
+
+func TestMemoRegexp(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		line     string
+		match    bool
+		num      string
+		id       string
+	}{
+		{
+			name:     "default template",
+			template: DefaultMemoTemplate,
+			line:     `Program log: Memo (len 30): "memobench: Test 12 [abcd1234]"`,
+			match:    true,
+			num:      "12",
+			id:       "abcd1234",
+		},
+		{
+			name:     "large number",
+			template: DefaultMemoTemplate,
+			line:     `Program log: Memo (len 35): "memobench: Test 1234567 [0f0f0f0f]"`,
+			match:    true,
+			num:      "1234567",
+			id:       "0f0f0f0f",
+		},
+		{
+			name:     "id before num",
+			template: "{id}-{num}",
+			line:     `Program log: Memo (len 11): "abcd1234-42"`,
+			match:    true,
+			num:      "42",
+			id:       "abcd1234",
+		},
+		{
+			name:     "escaped quotes",
+			template: `say "hi" {num} {id}`,
+			line:     `Program log: Memo (len 22): "say \"hi\" 3 abcd1234"`,
+			match:    true,
+			num:      "3",
+			id:       "abcd1234",
+		},
+		{
+			name:     "escaped backslash",
+			template: `path\to {num} {id}`,
+			line:     `Program log: Memo (len 18): "path\\to 3 abcd1234"`,
+			match:    true,
+			num:      "3",
+			id:       "abcd1234",
+		},
+		{
+			name:     "unescaped quotes and backslash",
+			template: `"a\b" {num} {id}`,
+			line:     `"a\b" 3 abcd1234`,
+			match:    true,
+			num:      "3",
+			id:       "abcd1234",
+		},
+		{
+			name:     "regexp metacharacters",
+			template: "bench (run) {num}.{id}?",
+			line:     `Program log: Memo (len 23): "bench (run) 5.abcd1234?"`,
+			match:    true,
+			num:      "5",
+			id:       "abcd1234",
+		},
+		{
+			name:     "extra whitespace",
+			template: DefaultMemoTemplate,
+			line:     `Program log: Memo (len 33): "memobench:   Test  12 [abcd1234]"`,
+			match:    true,
+			num:      "12",
+			id:       "abcd1234",
+		},
+		{
+			name:     "other whitespace",
+			template: DefaultMemoTemplate,
+			line:     "Program log: Memo (len 30): \"memobench:\tTest\n12 [abcd1234]\"",
+			match:    true,
+			num:      "12",
+			id:       "abcd1234",
+		},
+		{
+			name:     "collapsed whitespace",
+			template: "memobench:  Test {num} [{id}]",
+			line:     `Program log: Memo (len 30): "memobench: Test 12 [abcd1234]"`,
+			match:    true,
+			num:      "12",
+			id:       "abcd1234",
+		},
+		{
+			name:     "missing whitespace",
+			template: DefaultMemoTemplate,
+			line:     `Program log: Memo (len 29): "memobench:Test 12 [abcd1234]"`,
+		},
+		{
+			name:     "missing whitespace before the number",
+			template: DefaultMemoTemplate,
+			line:     `Program log: Memo (len 29): "memobench: Test12 [abcd1234]"`,
+		},
+		{
+			name:     "other program log",
+			template: DefaultMemoTemplate,
+			line:     "Program log: Instruction: Transfer",
+		},
+		{
+			name:     "compute units",
+			template: DefaultMemoTemplate,
+			line:     "Program MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr consumed 7015 of 200000 compute units",
+		},
+		{
+			name:     "warmup memo",
+			template: DefaultMemoTemplate,
+			line:     `Program log: Memo (len 32): "memobench: Warmup 1 [abcd1234]"`,
+		},
+		{
+			name:     "missing id",
+			template: DefaultMemoTemplate,
+			line:     `Program log: Memo (len 22): "memobench: Test 12 []"`,
+		},
+		{
+			name:     "non numeric num",
+			template: DefaultMemoTemplate,
+			line:     `Program log: Memo (len 31): "memobench: Test abc [abcd1234]"`,
+		},
+		{
+			name:     "unescaped metacharacters",
+			template: "bench (run) {num}.{id}?",
+			line:     `Program log: Memo (len 21): "bench run 5xabcd1234"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := MemoRegexp(tt.template)
+
+			matches := re.FindStringSubmatch(tt.line)
+			if !tt.match {
+				if matches != nil {
+					t.Fatalf("expected no match for %q, got %q", tt.line, matches)
+				}
+				return
+			}
+
+			if matches == nil {
+				t.Fatalf("expected a match for %q with %s", tt.line, re)
+			}
+
+			if num := matches[re.SubexpIndex("num")]; num != tt.num {
+				t.Errorf("num = %q, want %q", num, tt.num)
+			}
+			if id := matches[re.SubexpIndex("id")]; id != tt.id {
+				t.Errorf("id = %q, want %q", id, tt.id)
+			}
+		})
+	}
+}
+
+func TestMemoLiteralPattern(t *testing.T) {
+	tests := []struct {
+		literal string
+		want    string
+	}{
+		{"", ""},
+		{"memobench:", "memobench:"},
+		{"a b", `a\s+b`},
+		{"a \t\n b", `a\s+b`},
+		{" a ", `\s+a\s+`},
+		{`"`, `\\?"`},
+		{`\`, `\\?\\`},
+		{"[x]", `\[x\]`},
+		{"(a.b)?", `\(a\.b\)\?`},
+	}
+
+	for _, tt := range tests {
+		if got := memoLiteralPattern(tt.literal); got != tt.want {
+			t.Errorf("memoLiteralPattern(%q) = %q, want %q", tt.literal, got, tt.want)
+		}
+	}
+}
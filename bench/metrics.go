@@ -0,0 +1,69 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	MetricsServer *http.Server
+
+	SentCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "memobench_transactions_sent_total",
+		Help: "Number of transactions successfully sent to the RPC",
+	}, []string{"test_id", "endpoint"})
+
+	LandedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "memobench_transactions_landed_total",
+		Help: "Number of sent transactions that landed",
+	}, []string{"test_id", "endpoint"})
+
+	SendErrorCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "memobench_send_errors_total",
+		Help: "Number of transactions that failed to be sent, by error category",
+	}, []string{"test_id", "endpoint", "category"})
+
+	LandingHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "memobench_landing_time_seconds",
+		Help:    "Time between sending a transaction and seeing it land",
+		Buckets: prometheus.ExponentialBuckets(0.1, 1.5, 16),
+	}, []string{"test_id", "endpoint"})
+)
+
+// StartMetricsServer exposes the metrics in the prometheus format on the given address
+func StartMetricsServer(addr string) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(SentCounter, LandedCounter, SendErrorCounter, LandingHistogram)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	MetricsServer = &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := MetricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("error serving metrics: %v", err)
+		}
+	}()
+
+	log.Info("Serving metrics", "addr", addr)
+}
+
+func StopMetricsServer() {
+	if MetricsServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := MetricsServer.Shutdown(ctx); err != nil {
+		log.Errorf("error stopping metrics server: %v", err)
+	}
+}
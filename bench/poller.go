@@ -0,0 +1,81 @@
+package bench
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// StatusPoller detects the landed transactions by polling their signature statuses,
+// as an alternative or a complement to the websocket listener
+type StatusPoller struct {
+	Bench   *Benchmark
+	Client  *rpc.Client
+	Polling bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewStatusPoller(bench *Benchmark) *StatusPoller {
+	return &StatusPoller{
+		Bench:  bench,
+		Client: NewRpcClient(bench.Endpoint.RpcUrl),
+		stop:   make(chan struct{}),
+	}
+}
+
+func (p *StatusPoller) Start() {
+	defer p.Bench.wg.Done()
+
+	log.Info("Polling transaction statuses...", "endpoint", p.Bench.Endpoint.GetLabel(), "interval", StatusPollInterval)
+
+	ticker := time.NewTicker(StatusPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			log.Info("Stopping polling transaction statuses...")
+			return
+		case <-ticker.C:
+			p.Poll()
+
+			if p.Bench.AllTransactionsLanded() {
+				p.Bench.Stop()
+			}
+		}
+	}
+}
+
+// Poll records the landings of the outstanding transactions that reached the commitment level
+func (p *StatusPoller) Poll() {
+	p.Bench.SweepStatuses(p.Bench.ctx, p.Client, func(sig solana.Signature, slot uint64) bool {
+		// the landing may have already been recorded by the websocket listener
+		delta, found := p.Bench.RecordLanding(sig, slot)
+		if !found {
+			return false
+		}
+
+		log.Debug(
+			"Tx Processed",
+			"sig", sig.String(),
+			"delta", delta.Truncate(time.Millisecond).String(),
+			"landed", fmt.Sprintf("%d/%d", p.Bench.ProcessedTransactions, p.Bench.SentTransactions),
+			"source", "poll",
+		)
+
+		return true
+	})
+}
+
+func (p *StatusPoller) Stop() {
+	p.stopOnce.Do(func() {
+		p.Polling = false
+		close(p.stop)
+	})
+}
@@ -0,0 +1,46 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// ReportProgress periodically logs the sent and landed counts until the run is stopped,
+// to show the run is healthy without enabling the per-transaction logs
+func (b *Benchmark) ReportProgress() {
+	ticker := time.NewTicker(ProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopped:
+			return
+		case <-ticker.C:
+			b.logProgress()
+		}
+	}
+}
+
+func (b *Benchmark) logProgress() {
+	b.mu.RLock()
+	sent := b.SentTransactions
+	landed := b.ProcessedTransactions
+	built := b.DryRunTransactions
+	b.mu.RUnlock()
+
+	elapsed := time.Since(b.StartTime).Truncate(time.Second)
+
+	if GlobalConfig.DryRun {
+		log.Info("Progress", "built", built, "elapsed", elapsed)
+		return
+	}
+
+	landingRate := 0.0
+	if sent > 0 {
+		landingRate = float64(landed) / float64(sent) * 100
+	}
+
+	log.Info("Progress", "sent", sent, "landed", landed, "landing_rate", fmt.Sprintf("%.1f%%", landingRate), "elapsed", elapsed)
+}
@@ -0,0 +1,61 @@
+package bench
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// RampUp raises the send rate from rate_start to rate_end, in rate_steps steps of equal length
+// spanning the duration, the first step is set when the run is created
+func (b *Benchmark) RampUp() {
+	steps := GlobalConfig.GetRateSteps()
+
+	ticker := time.NewTicker(b.SendDeadline.Sub(b.SpamStartTime) / time.Duration(steps))
+	defer ticker.Stop()
+
+	for step := uint64(1); step < steps; step++ {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		b.mu.Lock()
+		b.rampRate = GlobalConfig.RampRate(step)
+		b.applyBackoff()
+		b.mu.Unlock()
+
+		log.Info("Raising the send rate", "step", step+1, "rate", b.Limiter.Limit())
+	}
+}
+
+// RateBandStats returns the results of the transactions sent during each step of the ramp, in the order of the steps
+func (b *Benchmark) RateBandStats() []*GroupStats {
+	keys := []string{}
+	for step := uint64(0); step < GlobalConfig.GetRateSteps(); step++ {
+		// close rates may round to the same step rate
+		key := strconv.FormatUint(GlobalConfig.RampRate(step), 10)
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return b.GroupStats(GroupRampRate, keys)
+}
+
+// FeeLevelStats returns the results of the transactions sent at each compute unit price of the sweep, in the order of the steps
+func (b *Benchmark) FeeLevelStats() []*GroupStats {
+	keys := []string{}
+	for step := uint64(0); step < GlobalConfig.GetFeeSteps(); step++ {
+		// close prices may round to the same step price
+		key := strconv.FormatUint(GlobalConfig.FeeLevel(step), 10)
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return b.GroupStats(GroupFeeLevel, keys)
+}
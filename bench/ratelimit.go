@@ -0,0 +1,80 @@
+package bench
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"golang.org/x/time/rate"
+)
+
+// IsRateLimitError reports whether the error is the RPC rejecting a request for exceeding its rate limit
+func IsRateLimitError(err error) bool {
+	var httpErr *jsonrpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code == http.StatusTooManyRequests {
+		return true
+	}
+
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == http.StatusTooManyRequests || strings.Contains(strings.ToLower(rpcErr.Message), "rate limit")
+	}
+
+	return false
+}
+
+// Backoff halves the send rate after the RPC rate limited a transaction,
+// the rate is restored one step at a time after a jittered delay
+func (b *Benchmark) Backoff() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.RateLimitedTransactions += 1
+
+	// the concurrent sends are likely to be rate limited together, only back off once for them
+	if time.Since(b.lastBackoff) < RateLimitBackoffInterval || b.backoffLevel >= MaxBackoffLevel {
+		return
+	}
+
+	b.lastBackoff = time.Now()
+	b.backoffLevel += 1
+	b.applyBackoff()
+
+	log.Warn("Rate limited by the RPC, backing off", "level", b.backoffLevel, "rate", b.Limiter.Limit())
+
+	delay := RateLimitRecoveryDelay + time.Duration(rand.Int63n(int64(RateLimitRecoveryDelay)))
+	time.AfterFunc(delay, b.recoverRate)
+}
+
+func (b *Benchmark) recoverRate() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.backoffLevel == 0 {
+		return
+	}
+
+	b.backoffLevel -= 1
+	b.applyBackoff()
+
+	log.Info("Recovering from the rate limit", "level", b.backoffLevel, "rate", b.Limiter.Limit())
+}
+
+// applyBackoff sets the limiter rate for the current backoff level, it must be called with the lock held,
+// the burst is capped by the reduced rate
+func (b *Benchmark) applyBackoff() {
+	// the rate of the current step when ramping up
+	target := GlobalConfig.RateLimit
+	if b.rampRate > 0 {
+		target = b.rampRate
+	}
+
+	limit := max(float64(target)/float64(uint64(1)<<b.backoffLevel), 1)
+
+	b.Limiter.SetLimit(rate.Limit(limit))
+	b.Limiter.SetBurst(int(min(float64(GlobalConfig.GetBurst()), limit)))
+}
@@ -0,0 +1,152 @@
+package bench
+
+import (
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// WatchResends resends the transactions whose blockhash expired before they landed with a fresh blockhash,
+// up to max_resends times each, and stops the run once every transaction landed or expired after its last resend
+func (b *Benchmark) WatchResends(rpcClient *rpc.Client, sendClients []*rpc.Client) {
+	ticker := time.NewTicker(ExpiryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// the height is read at the listener commitment, so that the landings
+		// of the last valid block had the time to reach the commitment
+		height, err := rpcClient.GetBlockHeight(b.ctx, GlobalConfig.GetCommitment())
+		if err != nil {
+			log.Warn("Unable to get the block height", "err", err)
+			continue
+		}
+
+		expired, waiting := b.ExpiredTransactions(height)
+		if !waiting {
+			log.Info("Every transaction landed or expired after its last resend, stopping the run", "block_height", height)
+			b.Stop()
+			return
+		}
+
+		if len(expired) == 0 {
+			continue
+		}
+
+		// the latest blockhash may be about to expire if its refresh failed
+		if lastValidBlockHeight, _ := b.BlockhashExpiry(); lastValidBlockHeight <= height+BlockhashExpiryMargin {
+			recent, err := rpcClient.GetLatestBlockhash(b.ctx, GlobalConfig.GetBlockhashCommitment())
+			if err != nil {
+				log.Warn("Unable to refresh the blockhash", "err", err)
+			} else {
+				b.SetBlockhash(recent.Value.Blockhash, recent.Value.LastValidBlockHeight, recent.Context.Slot)
+			}
+		}
+
+		log.Info("Resending the expired transactions", "count", len(expired), "block_height", height)
+
+		for _, num := range expired {
+			go b.Resend(sendClients, num)
+		}
+	}
+}
+
+// ExpiredTransactions flags the transactions whose blockhash expired before they landed as resent,
+// and returns their numbers, along with whether any transaction may still land or be resent
+func (b *Benchmark) ExpiredTransactions(height uint64) ([]uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expired := []uint64{}
+	waiting := !b.SendingDone || b.resendsInFlight > 0
+
+	for _, record := range b.TxRecords {
+		if record.Landed || record.Resent {
+			continue
+		}
+
+		// the margin leaves the time for the landings of the last valid block to be notified
+		if height <= record.LastValidBlockHeight+BlockhashExpiryMargin {
+			waiting = true
+			continue
+		}
+
+		// given up on after its last resend
+		if record.Attempt > GlobalConfig.MaxResends {
+			continue
+		}
+
+		record.Resent = true
+		b.ResentTransactions += 1
+		b.resendsInFlight += 1
+		b.resendAttempts[record.Num] = record.Attempt + 1
+
+		expired = append(expired, record.Num)
+		waiting = true
+	}
+
+	return expired, waiting
+}
+
+// Resend sends the transaction number again with the latest blockhash, paced by the rate limiter
+func (b *Benchmark) Resend(sendClients []*rpc.Client, num uint64) {
+	defer func() {
+		b.mu.Lock()
+		b.resendsInFlight -= 1
+		b.mu.Unlock()
+	}()
+
+	if err := b.Limiter.Wait(b.ctx); err != nil {
+		return
+	}
+
+	b.SubmitTransaction(sendClients, num, b.BuildTransaction(num, b.LatestBlockhash()))
+}
+
+// ResendStats returns the number of distinct transaction numbers sent and landed,
+// and the average number of attempts the landed ones took
+func (b *Benchmark) ResendStats() (sent int, landed int, avgAttempts float64) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	nums := make(map[uint64]bool)
+	attempts := uint64(0)
+	for _, record := range b.TxRecords {
+		nums[record.Num] = nums[record.Num] || record.Landed
+
+		if record.Landed {
+			attempts += record.Attempt
+		}
+	}
+
+	for _, numLanded := range nums {
+		if numLanded {
+			landed++
+		}
+	}
+
+	// the records streamed out all landed, the expired attempts before their resends are still kept
+	if b.Streamed != nil {
+		landed += int(b.Streamed.Count)
+		attempts += b.Streamed.Attempts
+		sent := len(nums) + int(b.Streamed.Count-b.Streamed.Resent)
+
+		if landed > 0 {
+			avgAttempts = float64(attempts) / float64(landed)
+		}
+
+		return sent, landed, avgAttempts
+	}
+
+	if landed > 0 {
+		avgAttempts = float64(attempts) / float64(landed)
+	}
+
+	return len(nums), landed, avgAttempts
+}
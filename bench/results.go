@@ -0,0 +1,672 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+type Results struct {
+	TestID    string    `json:"test_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Wallet    string    `json:"wallet"`
+	Config    Config    `json:"config"`
+
+	// one entry per benchmarked endpoint and run, in order
+	Endpoints []EndpointResults `json:"endpoints"`
+
+	// the results across the runs of each endpoint, only set when the test is repeated
+	Aggregates []AggregateResults `json:"aggregates,omitempty"`
+
+	// the endpoints ranked by landing rate then median landing time, only set when several endpoints are compared
+	Leaderboard []LeaderboardEntry `json:"leaderboard,omitempty"`
+}
+
+// mean and standard deviation across the runs
+type AggregateResults struct {
+	Label             string  `json:"label"`
+	Runs              int     `json:"runs"`
+	LandingRateMean   float64 `json:"landing_rate_mean"`
+	LandingRateStdDev float64 `json:"landing_rate_stddev"`
+	MedianMean        float64 `json:"median_ms_mean"`
+	MedianStdDev      float64 `json:"median_ms_stddev"`
+	P90Mean           float64 `json:"p90_ms_mean"`
+	P90StdDev         float64 `json:"p90_ms_stddev"`
+}
+
+type EndpointResults struct {
+	TestID           string    `json:"test_id"`
+	Run              uint64    `json:"run"`
+	Label            string    `json:"label"`
+	RpcUrl           string    `json:"rpc_url"`
+	WsUrl            string    `json:"ws_url"`
+	SendRpcUrl       string    `json:"send_rpc_url"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	ComputeUnitPrice uint64    `json:"compute_unit_price_microlamports"`
+
+	WarmupTransactions uint64  `json:"warmup_transactions"`
+	SentTransactions   uint64  `json:"sent_transactions"`
+	LandedTransactions uint64  `json:"landed_transactions"`
+	LandingRate        float64 `json:"landing_rate"`
+
+	// set when the run was stopped by the early abort check, the results are partial
+	AbortedEarly bool `json:"aborted_early,omitempty"`
+
+	// landings seen before their send time, excluded from the landing times
+	NegativeDeltas uint64 `json:"negative_deltas,omitempty"`
+
+	// landings on blocks reorged out, out of the ones checked once finalized, only set if verify_blocks is enabled
+	ReorgedLandings    uint64 `json:"reorged_landings,omitempty"`
+	ReincludedLandings uint64 `json:"reincluded_landings,omitempty"`
+	LandingsVerified   uint64 `json:"landings_verified,omitempty"`
+
+	// number of batch requests sent, only set if send_batch_size is greater than 1
+	SendBatches uint64 `json:"send_batches,omitempty"`
+
+	// only set with max_resends, the sent and landed counts above include every attempt,
+	// these count the distinct transaction numbers, each landed after avg_attempts_to_land attempts
+	ResentTransactions     uint64  `json:"resent_transactions,omitempty"`
+	LandedWithResends      int     `json:"landed_with_resends,omitempty"`
+	LandingRateWithResends float64 `json:"landing_rate_with_resends,omitempty"`
+	AvgAttemptsToLand      float64 `json:"avg_attempts_to_land,omitempty"`
+
+	// number of times a transaction was rate limited by the RPC
+	RateLimitedTransactions uint64 `json:"rate_limited_transactions"`
+
+	// time the transactions waited for the rate limiter, only set in tx count mode
+	ThrottleDelays *ThrottleResult `json:"throttle_delays,omitempty"`
+
+	// transactions built but not sent in dry run mode
+	DryRunTransactions uint64 `json:"dry_run_transactions,omitempty"`
+
+	// transactions whose submission timed out, not included in the sent ones
+	TimedOutTransactions uint64 `json:"timed_out_transactions"`
+
+	// transactions rejected by the preflight simulation, not included in the sent ones
+	PreflightFailedTransactions uint64 `json:"preflight_failed_transactions"`
+
+	// transactions that failed to be sent, by error category
+	SendErrors map[string]uint64 `json:"send_errors,omitempty"`
+
+	// the average serialized size of the sent transactions in bytes
+	AvgTxSize float64 `json:"avg_tx_size_bytes"`
+
+	// the time of the first RPC request and of the websocket handshake, measured once before the test
+	RpcRoundTrip float64 `json:"rpc_round_trip_ms,omitempty"`
+	WsConnect    float64 `json:"ws_connect_ms,omitempty"`
+
+	// the time from the start of the send window until the first and the last landings, omitted if none landed
+	TimeToFirstLanding float64 `json:"time_to_first_landing_ms,omitempty"`
+	TimeToLastLanding  float64 `json:"time_to_last_landing_ms,omitempty"`
+
+	// the achieved throughput, in transactions per second
+	SendRate float64 `json:"send_rate"`
+	LandRate float64 `json:"land_rate"`
+
+	// landing times are omitted if no transaction landed
+	LandingTimes *LandingTimesResult `json:"landing_times,omitempty"`
+
+	// the landing time percentiles set with percentiles, keyed by their label, e.g. p99.9
+	LandingPercentiles map[string]float64 `json:"landing_percentiles_ms,omitempty"`
+
+	// slot distances are omitted if the send slots are unknown
+	SlotDistances *SlotDistancesResult `json:"slot_distances,omitempty"`
+
+	// number of slots between the blockhash and landing slots, omitted if no transaction landed
+	InclusionSlotOffsets *SlotDistancesResult `json:"inclusion_slot_offsets,omitempty"`
+
+	// time the RPC took to accept the transactions, omitted if none was sent
+	SendLatencies *LandingTimesResult `json:"send_latencies,omitempty"`
+
+	// time to reach each commitment level, only set if track_all_commitments is enabled
+	CommitmentLifecycle map[rpc.CommitmentType]*LandingTimesResult `json:"commitment_lifecycle,omitempty"`
+
+	Blocks []BlockResult `json:"blocks"`
+
+	// only set when several test wallets are used
+	Wallets []GroupResults `json:"wallets,omitempty"`
+
+	// only set when several send urls are used
+	SendUrls []GroupResults `json:"send_urls,omitempty"`
+
+	// only set with cross_check_ws_url
+	CrossCheck *CrossCheckResult `json:"cross_check,omitempty"`
+
+	// only set with control_rate
+	Control *ControlResult `json:"control,omitempty"`
+
+	// the results of each compute unit price of the sweep, only set with fee_min and fee_max
+	FeeLevels []GroupResults `json:"fee_levels,omitempty"`
+
+	// the results of each step of the ramp, only set with rate_start and rate_end
+	RateBands []GroupResults `json:"rate_bands,omitempty"`
+
+	// signatures of the sent transactions that never landed
+	DroppedSignatures []string `json:"dropped_signatures"`
+}
+
+// landing times in milliseconds
+type LandingTimesResult struct {
+	Min    float64 `json:"min_ms"`
+	Max    float64 `json:"max_ms"`
+	Avg    float64 `json:"avg_ms"`
+	Median float64 `json:"median_ms"`
+	P90    float64 `json:"p90_ms"`
+	P95    float64 `json:"p95_ms"`
+	P99    float64 `json:"p99_ms"`
+	StdDev float64 `json:"stddev_ms"`
+	IQR    float64 `json:"iqr_ms"`
+}
+
+// the time the transactions waited for the rate limiter before being sent
+type ThrottleResult struct {
+	Min       float64 `json:"min_ms"`
+	Avg       float64 `json:"avg_ms"`
+	Max       float64 `json:"max_ms"`
+	Throttled uint64  `json:"throttled"`
+}
+
+// the landings of the control stream, sent at control_compute_unit_price_microlamports
+type ControlResult struct {
+	Sent             uint64              `json:"sent"`
+	Landed           uint64              `json:"landed"`
+	LandingRate      float64             `json:"landing_rate"`
+	ComputeUnitPrice uint64              `json:"compute_unit_price_microlamports"`
+	LandingTimes     *LandingTimesResult `json:"landing_times,omitempty"`
+}
+
+// number of slots between the send, or blockhash, and landing slots
+type SlotDistancesResult struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Avg    float64 `json:"avg"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+	P95    float64 `json:"p95"`
+	P99    float64 `json:"p99"`
+}
+
+// the results of the transactions of a single wallet, send url or ramp step
+type GroupResults struct {
+	Wallet             string              `json:"wallet,omitempty"`
+	SendUrl            string              `json:"send_url,omitempty"`
+	SendRate           uint64              `json:"send_rate,omitempty"`
+	ComputeUnitPrice   uint64              `json:"compute_unit_price_microlamports,omitempty"`
+	SentTransactions   uint64              `json:"sent_transactions"`
+	LandedTransactions uint64              `json:"landed_transactions"`
+	LandingRate        float64             `json:"landing_rate"`
+	LandingTimes       *LandingTimesResult `json:"landing_times,omitempty"`
+}
+
+// the landings notified by the cross-check websocket, compared with the ones of the run
+type CrossCheckResult struct {
+	WsUrl              string   `json:"ws_url"`
+	LandedTransactions int      `json:"landed_transactions"`
+	MissedByCrossCheck []string `json:"missed_by_cross_check"`
+	MissedByEndpoint   []string `json:"missed_by_endpoint"`
+
+	// set if the connection was lost during the run, the cross-check is then incomplete
+	Lost bool `json:"lost,omitempty"`
+}
+
+type BlockResult struct {
+	Slot  uint64 `json:"slot"`
+	Count uint64 `json:"count"`
+
+	// only set if verify_blocks is enabled
+	Status string `json:"status,omitempty"`
+
+	// the validator identity of the slot leader, omitted if the leader schedule couldn't be fetched
+	Leader string `json:"leader,omitempty"`
+
+	// the median landing time of the transactions of the block, omitted if none of them has a known landing time
+	MedianLandingMs float64 `json:"median_landing_ms,omitempty"`
+}
+
+func durationToMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// NewGroupResults converts the stats of a group, the key is set by the caller
+func NewSlotDistancesResult(distance SlotStats) *SlotDistancesResult {
+	return &SlotDistancesResult{
+		Min:    distance.Min,
+		Max:    distance.Max,
+		Avg:    distance.Avg,
+		Median: distance.Median,
+		P90:    distance.P90,
+		P95:    distance.P95,
+		P99:    distance.P99,
+	}
+}
+
+func NewGroupResults(stats *GroupStats) GroupResults {
+	out := GroupResults{
+		SentTransactions:   stats.Sent,
+		LandedTransactions: stats.Landed,
+	}
+
+	if landing, ok := stats.LandingStats(); ok {
+		out.LandingTimes = newLandingTimesResult(landing)
+	}
+
+	if stats.Sent > 0 {
+		out.LandingRate = float64(stats.Landed) / float64(stats.Sent)
+	}
+
+	return out
+}
+
+// NewLandingTimesResult converts the landing time stats of the deltas to milliseconds,
+// it returns nil if there's no delta
+func NewLandingTimesResult(deltas []time.Duration) *LandingTimesResult {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	return newLandingTimesResult(ComputeLandingStats(deltas))
+}
+
+func newLandingTimesResult(landing LandingStats) *LandingTimesResult {
+	return &LandingTimesResult{
+		Min:    durationToMs(landing.Min),
+		Max:    durationToMs(landing.Max),
+		Avg:    durationToMs(landing.Avg),
+		Median: durationToMs(landing.Median),
+		P90:    durationToMs(landing.P90),
+		P95:    durationToMs(landing.P95),
+		P99:    durationToMs(landing.P99),
+		StdDev: durationToMs(landing.StdDev),
+		IQR:    durationToMs(landing.IQR),
+	}
+}
+
+func BuildResults(benchmarks []*Benchmark) *Results {
+	out := &Results{
+		TestID:    TestID,
+		StartTime: TestStartTime.UTC(),
+		EndTime:   TestEndTime.UTC(),
+		Wallet:    TestAccount.PublicKey().String(),
+		Config:    GlobalConfig.Redacted(),
+		Endpoints: []EndpointResults{},
+	}
+
+	for _, b := range benchmarks {
+		out.Endpoints = append(out.Endpoints, b.BuildResults())
+	}
+
+	if GlobalConfig.GetRepeat() > 1 {
+		for _, aggregate := range BuildAggregates(benchmarks) {
+			result := AggregateResults{Label: aggregate.Label, Runs: aggregate.Runs}
+			result.LandingRateMean, result.LandingRateStdDev = Spread(aggregate.LandingRates)
+			result.MedianMean, result.MedianStdDev = Spread(aggregate.Medians)
+			result.P90Mean, result.P90StdDev = Spread(aggregate.P90s)
+
+			out.Aggregates = append(out.Aggregates, result)
+		}
+	}
+
+	if len(GlobalConfig.GetEndpoints()) > 1 && !GlobalConfig.DryRun {
+		out.Leaderboard = BuildLeaderboard(BuildAggregates(benchmarks))
+	}
+
+	return out
+}
+
+func (b *Benchmark) BuildResults() EndpointResults {
+	sendRate := b.AchievedSendRate()
+	landRate := b.AchievedLandRate()
+	avgTxSize := b.AverageTxSize()
+
+	dropped := []string{}
+	for _, record := range b.DroppedTransactions() {
+		dropped = append(dropped, record.Signature.String())
+	}
+
+	wallets := []GroupResults{}
+	if len(TestAccounts) > 1 {
+		for _, stats := range b.WalletStats() {
+			wallet := NewGroupResults(stats)
+			wallet.Wallet = stats.Key
+			wallets = append(wallets, wallet)
+		}
+	}
+
+	sendUrls := []GroupResults{}
+	if GlobalConfig.GetSendMode() == SendModeRpc && len(b.Endpoint.GetSendUrls()) > 1 {
+		for _, stats := range b.SendUrlStats() {
+			sendUrl := NewGroupResults(stats)
+			sendUrl.SendUrl = stats.Key
+			sendUrls = append(sendUrls, sendUrl)
+		}
+	}
+
+	rateBands := []GroupResults{}
+	if GlobalConfig.IsRamp() {
+		for _, stats := range b.RateBandStats() {
+			rateBand := NewGroupResults(stats)
+			rateBand.SendRate, _ = strconv.ParseUint(stats.Key, 10, 64)
+			rateBands = append(rateBands, rateBand)
+		}
+	}
+
+	feeLevels := []GroupResults{}
+	if GlobalConfig.IsFeeSweep() {
+		for _, stats := range b.FeeLevelStats() {
+			feeLevel := NewGroupResults(stats)
+			feeLevel.ComputeUnitPrice, _ = strconv.ParseUint(stats.Key, 10, 64)
+			feeLevels = append(feeLevels, feeLevel)
+		}
+	}
+
+	var crossCheck *CrossCheckResult
+	if b.CrossCheck != nil {
+		seen, missedByCrossCheck, missedByEndpoint := b.CrossCheckStats()
+		crossCheck = &CrossCheckResult{
+			WsUrl:              RedactUrl(b.CrossCheck.Url),
+			LandedTransactions: seen,
+			MissedByCrossCheck: []string{},
+			MissedByEndpoint:   []string{},
+			Lost:               b.CrossCheck.Lost,
+		}
+		for _, sig := range missedByCrossCheck {
+			crossCheck.MissedByCrossCheck = append(crossCheck.MissedByCrossCheck, sig.String())
+		}
+		for _, sig := range missedByEndpoint {
+			crossCheck.MissedByEndpoint = append(crossCheck.MissedByEndpoint, sig.String())
+		}
+	}
+
+	resendSent, resendLanded, avgAttempts := 0, 0, 0.0
+	if GlobalConfig.MaxResends > 0 {
+		resendSent, resendLanded, avgAttempts = b.ResendStats()
+	}
+
+	sendBatches, _ := b.SendBatches()
+	firstLanding, lastLanding, _ := b.LandingWindow()
+	blockLatencies := b.BlockLatencies()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	out := EndpointResults{
+		Label:                       b.Endpoint.GetLabel(),
+		RpcUrl:                      b.Endpoint.RpcUrl,
+		WsUrl:                       b.Endpoint.GetWsUrl(),
+		SendRpcUrl:                  b.Endpoint.GetSendUrl(),
+		StartTime:                   b.StartTime.UTC(),
+		EndTime:                     b.EndTime.UTC(),
+		ComputeUnitPrice:            b.ComputeUnitPrice,
+		WarmupTransactions:          b.WarmupTransactions,
+		SentTransactions:            b.SentTransactions,
+		LandedTransactions:          b.ProcessedTransactions,
+		TimedOutTransactions:        b.TimedOutTransactions,
+		PreflightFailedTransactions: b.PreflightFailedTransactions,
+		DryRunTransactions:          b.DryRunTransactions,
+		RateLimitedTransactions:     b.RateLimitedTransactions,
+		ResentTransactions:          b.ResentTransactions,
+		LandedWithResends:           resendLanded,
+		AvgAttemptsToLand:           avgAttempts,
+		SendErrors:                  maps.Clone(b.SendErrors),
+		SendBatches:                 sendBatches,
+		AbortedEarly:                b.AbortedEarly,
+		NegativeDeltas:              b.NegativeDeltas,
+		ReorgedLandings:             b.ReorgedLandings,
+		ReincludedLandings:          b.ReincludedLandings,
+		LandingsVerified:            b.LandingsVerified,
+		AvgTxSize:                   avgTxSize,
+		RpcRoundTrip:                durationToMs(EndpointConnectionTimes[b.Endpoint.GetLabel()].RpcRoundTrip),
+		WsConnect:                   durationToMs(EndpointConnectionTimes[b.Endpoint.GetLabel()].WsConnect),
+		TimeToFirstLanding:          durationToMs(firstLanding),
+		TimeToLastLanding:           durationToMs(lastLanding),
+		SendRate:                    sendRate,
+		LandRate:                    landRate,
+		Blocks:                      []BlockResult{},
+		Wallets:                     wallets,
+		SendUrls:                    sendUrls,
+		RateBands:                   rateBands,
+		FeeLevels:                   feeLevels,
+		CrossCheck:                  crossCheck,
+		DroppedSignatures:           dropped,
+	}
+
+	if b.SentTransactions > 0 {
+		out.LandingRate = float64(b.ProcessedTransactions) / float64(b.SentTransactions)
+	}
+	if resendSent > 0 {
+		out.LandingRateWithResends = float64(resendLanded) / float64(resendSent)
+	}
+
+	if landing, ok := b.LandingTimeStats(); ok {
+		out.LandingTimes = newLandingTimesResult(landing)
+
+		if len(GlobalConfig.Percentiles) > 0 {
+			out.LandingPercentiles = make(map[string]float64)
+			for i, value := range b.LandingTimePercentiles() {
+				out.LandingPercentiles[strings.ToLower(FormatPercentile(GlobalConfig.Percentiles[i]))] = durationToMs(value)
+			}
+		}
+	}
+
+	if b.Throttle.Count > 0 {
+		out.ThrottleDelays = &ThrottleResult{
+			Min:       durationToMs(b.Throttle.Min),
+			Avg:       durationToMs(b.Throttle.Avg()),
+			Max:       durationToMs(b.Throttle.Max),
+			Throttled: b.Throttle.Throttled,
+		}
+	}
+
+	if distance, ok := b.SlotDistanceStats(); ok {
+		out.SlotDistances = NewSlotDistancesResult(distance)
+	}
+
+	if offset, ok := b.InclusionOffsetStats(); ok {
+		out.InclusionSlotOffsets = NewSlotDistancesResult(offset)
+	}
+
+	if latency, ok := b.SendLatencyStats(); ok {
+		out.SendLatencies = newLandingTimesResult(latency)
+	}
+
+	if b.Control != nil {
+		out.Control = b.Control.Result()
+	}
+
+	if GlobalConfig.TrackAllCommitments {
+		out.CommitmentLifecycle = make(map[rpc.CommitmentType]*LandingTimesResult)
+		for _, commitment := range LifecycleCommitments {
+			if result := NewLandingTimesResult(b.CommitmentDeltas[commitment]); result != nil {
+				out.CommitmentLifecycle[commitment] = result
+			}
+		}
+	}
+
+	for slot, count := range b.TxBlocks {
+		block := BlockResult{Slot: slot, Count: count, Status: b.BlockStatuses[slot], MedianLandingMs: durationToMs(blockLatencies[slot])}
+		if leader, ok := b.SlotLeaders[slot]; ok {
+			block.Leader = leader.String()
+		}
+		out.Blocks = append(out.Blocks, block)
+	}
+
+	sort.Slice(out.Blocks, func(i, j int) bool {
+		return out.Blocks[i].Slot < out.Blocks[j].Slot
+	})
+
+	return out
+}
+
+// EncodeResults returns the results as saved to the results file
+func EncodeResults(results *Results) ([]byte, error) {
+	return json.MarshalIndent(results, "", "  ")
+}
+
+// WriteResults saves the results file, to results_output if set, and returns where it was saved
+func WriteResults(results *Results) (string, error) {
+	data, err := EncodeResults(results)
+	if err != nil {
+		return "", err
+	}
+
+	return SaveResultsFile(ResultsFileName, "application/json", data)
+}
+
+// the columns of the csv file
+var TxRecordsHeader = []string{"endpoint", "signature", "num", "send_time", "landing_time", "delta_ms", "slot", "send_slot", "wallet", "size", "send_url", "blockhash_slot", "processed_ms", "confirmed_ms", "finalized_ms", "send_latency_ms", "compute_unit_price"}
+
+// WriteTxRecords writes one csv row per sent transaction, to results_output if set,
+// and returns where the file was saved, transactions that never landed have empty landing columns,
+// with streaming_stats, the rows of the records left are appended to the ones written as they landed
+func WriteTxRecords(benchmarks []*Benchmark) (string, error) {
+	if TxRecordsFile != nil {
+		return finishTxRecordsFile(benchmarks)
+	}
+
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	w.Write(TxRecordsHeader)
+
+	for _, b := range benchmarks {
+		for _, record := range b.sortedRecords() {
+			w.Write(TxRecordRow(b.Label(), record))
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+
+	return SaveResultsFile(TxRecordsFileName, "text/csv", buf.Bytes())
+}
+
+// finishTxRecordsFile appends the records left to the csv file written with streaming_stats,
+// which is then uploaded to results_output if set
+func finishTxRecordsFile(benchmarks []*Benchmark) (string, error) {
+	for _, b := range benchmarks {
+		for _, record := range b.sortedRecords() {
+			writeTxRecordRow(b.Label(), record)
+		}
+	}
+
+	if err := CloseTxRecordsFile(); err != nil {
+		return "", err
+	}
+
+	if ResultsSink == nil {
+		return TxRecordsFileName, nil
+	}
+
+	data, err := os.ReadFile(TxRecordsFileName)
+	if err != nil {
+		return "", err
+	}
+
+	// the local file is kept if the upload failed, so that the records are never lost
+	location, err := ResultsSink.Write(filepath.Base(TxRecordsFileName), "text/csv", data)
+	if err != nil {
+		log.Errorf("error saving %s to results_output, keeping it next to the log file instead: %v", filepath.Base(TxRecordsFileName), err)
+		return TxRecordsFileName, nil
+	}
+
+	os.Remove(TxRecordsFileName)
+
+	return location, nil
+}
+
+// sortedRecords returns a copy of the records of the run, in send order
+func (b *Benchmark) sortedRecords() []*TxRecord {
+	b.mu.RLock()
+	records := make([]*TxRecord, 0, len(b.TxRecords))
+	for _, record := range b.TxRecords {
+		copied := *record
+		records = append(records, &copied)
+	}
+	b.mu.RUnlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Num < records[j].Num
+	})
+
+	return records
+}
+
+// TxRecordRow returns the csv row of the record of a transaction of the run with the given label
+func TxRecordRow(label string, record *TxRecord) []string {
+	row := []string{
+		label,
+		record.Signature.String(),
+		strconv.FormatUint(record.Num, 10),
+		"",
+		"",
+		"",
+		"",
+		"",
+		record.Wallet.String(),
+		strconv.Itoa(record.Size),
+		record.SendUrl,
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+	}
+
+	// the send time is unknown in listen only mode
+	if !record.SendTime.IsZero() {
+		row[3] = record.SendTime.UTC().Format(time.RFC3339Nano)
+	}
+
+	if record.SendLatency > 0 {
+		row[15] = strconv.FormatFloat(durationToMs(record.SendLatency), 'f', 3, 64)
+	}
+
+	// the price is unknown in listen only mode
+	if !record.SendTime.IsZero() {
+		row[16] = strconv.FormatUint(record.ComputeUnitPrice, 10)
+	}
+
+	if record.SendSlot > 0 {
+		row[7] = strconv.FormatUint(record.SendSlot, 10)
+	}
+
+	if record.BlockhashSlot > 0 {
+		row[11] = strconv.FormatUint(record.BlockhashSlot, 10)
+	}
+
+	for i, commitment := range LifecycleCommitments {
+		if delta, ok := record.CommitmentDeltas[commitment]; ok {
+			row[12+i] = strconv.FormatFloat(durationToMs(delta), 'f', 3, 64)
+		}
+	}
+
+	if record.Landed {
+		row[6] = strconv.FormatUint(record.Slot, 10)
+	}
+
+	// the landing time of backfilled transactions is unknown
+	if record.Landed && !record.Backfilled {
+		row[4] = record.LandTime.UTC().Format(time.RFC3339Nano)
+	}
+
+	if record.HasLandingTime() {
+		row[5] = strconv.FormatFloat(durationToMs(record.Delta), 'f', 3, 64)
+	}
+
+	return row
+}
@@ -0,0 +1,31 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// RetryRpc calls the startup rpc request until it succeeds, up to MaxRpcAttempts times,
+// so that a transient failure of the endpoint doesn't fail the test, the delay between
+// two attempts doubles after each one, and the last error is returned once they're exhausted
+func RetryRpc[T any](ctx context.Context, name string, request func(ctx context.Context) (T, error)) (T, error) {
+	delay := RpcRetryDelay
+	for attempt := 1; ; attempt++ {
+		out, err := request(ctx)
+		if err == nil || attempt >= MaxRpcAttempts || ctx.Err() != nil {
+			return out, err
+		}
+
+		log.Warn("RPC request failed, retrying", "request", name, "attempt", fmt.Sprintf("%d/%d", attempt, MaxRpcAttempts), "delay", delay, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return out, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
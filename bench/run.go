@@ -0,0 +1,715 @@
+package bench
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+var (
+	TestID string
+
+	// the timezone of the log timestamps
+	LogLocation = time.UTC
+
+	// variable for the log file; set to benchmark.log as a fallback
+	LogFileName string = "benchmark.log"
+
+	// the log file, shared by all the loggers
+	LogFile *os.File
+
+	// variable for the json results file; set to benchmark.json as a fallback
+	ResultsFileName string = "benchmark.json"
+
+	// variable for the per-transaction csv file; set to benchmark.csv as a fallback
+	TxRecordsFileName string = "benchmark.csv"
+
+	// variable for the ndjson events file, only written with events_file
+	EventsFileName string = "benchmark.ndjson"
+
+	// the time the test started and finished
+	TestStartTime time.Time
+	TestEndTime   time.Time
+
+	TestAccount *solana.PrivateKey
+
+	// all the test wallets, starting with the test account
+	TestAccounts []*solana.PrivateKey
+
+	// the runs of the test, one per endpoint, and the one in progress
+	Benchmarks       []*Benchmark
+	CurrentBenchmark *Benchmark
+
+	// ensures the results are only saved once, and keeps them
+	finishOnce   sync.Once
+	finalResults *Results
+
+	SimpleLogger *log.Logger
+
+	// logs the test settings at startup, hidden from the console in quiet mode
+	HeaderLogger *log.Logger
+
+	// only show the results on the console, the full log is still written to the log file
+	Quiet bool
+
+	// the format of the results summary, either plain lines or a table
+	SummaryFormat string = SummaryFormatPlain
+
+	// where the config was read from, shown in the header
+	ConfigSource string
+
+	// the results to compare the test with, and the path they were loaded from
+	Baseline     *Results
+	BaselineFile string
+
+	// the names of the flags explicitly set on the command line
+	SetFlags = make(map[string]bool)
+)
+
+// ValueSource returns where the value overridable by the given flag came from
+func ValueSource(flagName string) string {
+	if SetFlags[flagName] {
+		return "(flag)"
+	}
+
+	return "(config)"
+}
+
+// ApplyProxy routes the RPC and block engine requests of the test through the proxy, the clients
+// created afterwards use it, the process environment and the other http clients are left untouched
+func ApplyProxy(proxyUrl string) error {
+	u, err := url.Parse(proxyUrl)
+	if err != nil {
+		return fmt.Errorf("error parsing proxy_url: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("proxy_url scheme must be one of http, https, socks5 or socks5h, got %q", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("proxy_url must contain a host, got %q", proxyUrl)
+	}
+
+	Proxy = u
+	proxyHttpClient = newProxyHttpClient(u)
+
+	// the solana-go websocket dialer only honors the proxy environment variables
+	if GlobalConfig.SplitMode != SplitModeSendOnly {
+		log.Warn("The websocket connections don't go through proxy_url, only the RPC and block engine requests do", "proxy_url", RedactUrl(proxyUrl))
+	}
+
+	return nil
+}
+
+func VerifyPrivateKey(base58key string) (*solana.PrivateKey, error) {
+	account, err := solana.PrivateKeyFromBase58(base58key)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key: %w", err)
+	}
+
+	return &account, nil
+}
+
+// VerifyPrivateKeys loads the test account and the additional test wallets
+func VerifyPrivateKeys(config *Config) error {
+	account, err := VerifyPrivateKey(config.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	TestAccount = account
+	TestAccounts = []*solana.PrivateKey{TestAccount}
+
+	seen := map[solana.PublicKey]bool{TestAccount.PublicKey(): true}
+	for _, key := range config.PrivateKeys {
+		account, err := VerifyPrivateKey(key)
+		if err != nil {
+			return err
+		}
+
+		if seen[account.PublicKey()] {
+			return fmt.Errorf("duplicate test wallet %s", account.PublicKey())
+		}
+
+		seen[account.PublicKey()] = true
+		TestAccounts = append(TestAccounts, account)
+	}
+
+	return nil
+}
+
+// EstimateTestCost returns the estimated cost of the test for each test wallet, in lamports
+func EstimateTestCost() uint64 {
+	costPerTx := EstimateTxCost(GlobalConfig.GetComputeUnitPrice())
+
+	// the sweep reaches fee_max, account for it on all the transactions to be safe
+	if GlobalConfig.IsFeeSweep() {
+		costPerTx = EstimateTxCost(GlobalConfig.FeeMax)
+	}
+
+	// the jito tip is only paid by the bundles that land, but account for all of them
+	if GlobalConfig.GetSendMode() == SendModeJito {
+		costPerTx += GlobalConfig.JitoTip
+	}
+
+	// the transactions are spread evenly across the wallets, round up to be safe
+	wallets := uint64(len(TestAccounts))
+	txPerWallet := (GlobalConfig.GetExpectedTxCount() + wallets - 1) / wallets
+
+	// the control transactions are sent at their own price
+	controlPerWallet := (GlobalConfig.GetExpectedControlTxCount() + wallets - 1) / wallets
+	walletCost := txPerWallet*costPerTx + controlPerWallet*EstimateTxCost(GlobalConfig.ControlCuPrice)
+
+	return walletCost * uint64(len(GlobalConfig.GetEndpoints())) * GlobalConfig.GetRepeat()
+}
+
+// AssertSufficientBalance returns an error if a test wallet can't cover half of the estimated cost of the test
+func AssertSufficientBalance(ctx context.Context) error {
+	// Create a new RPC client:
+	rpcClient := NewRpcClient(GlobalConfig.GetEndpoints()[0].RpcUrl)
+
+	totalCost := EstimateTestCost()
+
+	for _, wallet := range TestAccounts {
+		balance, err := RetryRpc(ctx, "getBalance", func(ctx context.Context) (*rpc.GetBalanceResult, error) {
+			return rpcClient.GetBalance(ctx, wallet.PublicKey(), GlobalConfig.GetRpcCommitment())
+		})
+		if err != nil || balance == nil {
+			return fmt.Errorf("error getting test wallet balance: %v", err)
+		}
+
+		keyvals := []interface{}{
+			"wallet", wallet.PublicKey(),
+			"balance", fmt.Sprintf("%.6f SOL", float64(balance.Value)/float64(solana.LAMPORTS_PER_SOL)),
+			"required", fmt.Sprintf("%.6f SOL", float64(totalCost)/float64(solana.LAMPORTS_PER_SOL)),
+		}
+		log.Info("Test wallet balance", keyvals...)
+
+		if balance.Value >= totalCost/2 {
+			continue
+		}
+
+		// the estimate may be wrong, e.g. with sponsored fees
+		if GlobalConfig.SkipBalanceCheck {
+			log.Warn("Insufficient balance in test wallet, ignored since skip_balance_check is set", keyvals...)
+			continue
+		}
+
+		// abort if balance is less than 50% of the maximum cost
+		log.Error("Insufficient balance in test wallet.", keyvals...)
+		return fmt.Errorf("insufficient balance in test wallet %s", wallet.PublicKey())
+	}
+
+	return nil
+}
+
+// DisplayComparison logs a side by side comparison table of the benchmarked endpoints
+func DisplayComparison() {
+	width := len("Endpoint")
+	for _, b := range Benchmarks {
+		width = max(width, len(b.Label()))
+	}
+
+	SimpleLogger.Printf("")
+	SimpleLogger.Printf("%-*s | %-17s | %9s | %9s | %9s | %9s | %9s", width, "Endpoint", "Landed", "Min", "Median", "P90", "P95", "P99")
+	SimpleLogger.Printf("%s", strings.Repeat("-", width+80))
+
+	for _, b := range Benchmarks {
+		landed := fmt.Sprintf("%d/%d (%.1f%%)", b.ProcessedTransactions, b.SentTransactions, float64(b.ProcessedTransactions)/float64(b.SentTransactions)*100.0)
+
+		landing, ok := b.LandingTimeStats()
+		if !ok {
+			SimpleLogger.Printf("%-*s | %-17s | %9s | %9s | %9s | %9s | %9s", width, b.Label(), landed, "-", "-", "-", "-", "-")
+			continue
+		}
+
+		SimpleLogger.Printf("%-*s | %-17s | %9s | %9s | %9s | %9s | %9s",
+			width,
+			b.Label(),
+			landed,
+			landing.Min.Truncate(time.Millisecond),
+			landing.Median.Truncate(time.Millisecond),
+			landing.P90.Truncate(time.Millisecond),
+			landing.P95.Truncate(time.Millisecond),
+			landing.P99.Truncate(time.Millisecond),
+		)
+	}
+}
+
+// Finish compares the endpoints and saves the results of the runs completed so far,
+// it's called either at the end of the test or when the test is interrupted, and only runs once
+func Finish() *Results {
+	finishOnce.Do(func() {
+		TestEndTime = time.Now()
+
+		// the listener of the last endpoint is stopped, no more metrics to serve
+		StopMetricsServer()
+		StopTracing()
+
+		// compare the endpoints side by side, the tables show the full summaries
+		if len(Benchmarks) > 1 {
+			if SummaryFormat == SummaryFormatTable {
+				DisplaySummaries(Benchmarks)
+			} else {
+				DisplayComparison()
+			}
+		}
+
+		// rank the endpoints, across their runs
+		if len(GlobalConfig.GetEndpoints()) > 1 && !GlobalConfig.DryRun {
+			DisplayLeaderboard(BuildLeaderboard(BuildAggregates(Benchmarks)))
+		}
+
+		// summarize the repeated runs
+		if GlobalConfig.GetRepeat() > 1 {
+			DisplayAggregates(BuildAggregates(Benchmarks))
+		}
+
+		results := BuildResults(Benchmarks)
+		finalResults = results
+
+		// compare with the previous results
+		if Baseline != nil {
+			DisplayBaselineComparison(Baseline, results)
+		}
+
+		// save the structured results
+		resultsLocation, err := WriteResults(results)
+		if err != nil {
+			log.Errorf("error saving results file: %v", err)
+		}
+
+		// save the per-transaction records
+		recordsLocation, err := WriteTxRecords(Benchmarks)
+		if err != nil {
+			log.Errorf("error saving transaction records file: %v", err)
+		}
+
+		// notify the monitoring, once the results are saved
+		if GlobalConfig.WebhookUrl != "" {
+			if err := PostResults(GlobalConfig.WebhookUrl, results); err != nil {
+				log.Errorf("error posting results to the webhook: %v", err)
+			} else {
+				log.Info("Results posted to the webhook")
+			}
+		}
+
+		fmt.Println()
+		if LogFile != nil {
+			fmt.Printf("Benchmark results saved to %s, %s and %s\n", LogFileName, resultsLocation, recordsLocation)
+		} else {
+			fmt.Printf("Benchmark results saved to %s and %s\n", resultsLocation, recordsLocation)
+		}
+		if EventsFile != nil {
+			fmt.Printf("Transaction events saved to %s\n", EventsFileName)
+		}
+	})
+
+	return finalResults
+}
+
+// NewTestID generates a random id to tag the transactions of a test
+func NewTestID() string {
+	randomBytes := make([]byte, 4)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(randomBytes)
+}
+
+// Run benchmarks the endpoints of the config, as many times as requested, and returns the results,
+// which are also saved to the log, json and csv files, in config.LogDir if set.
+// The config must be complete, e.g. with its private key, the cancellation of the context stops the run
+// in progress and skips the remaining ones, the results of the completed runs are still returned.
+// A run that fails to start, e.g. when the websocket can't be connected, ends the test with its error,
+// along with the results of the runs completed before it, if any.
+// The state of the test is global and reset by each call, so a single test can run at a time
+func Run(ctx context.Context, config *Config) (*Results, error) {
+	ResetState()
+	GlobalConfig = config
+
+	// a fixed test id, e.g. to listen for the transactions of a sender
+	TestID = NewTestID()
+	if config.TestID != "" {
+		if err := ValidateTestID(config.TestID); err != nil {
+			return nil, err
+		}
+		TestID = config.TestID
+	}
+
+	// the timezone of the log timestamps, UTC if not set
+	location, err := time.LoadLocation(config.LogTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("log_timezone must be a timezone name, e.g. Europe/Paris, or Local, got %q", config.LogTimezone)
+	}
+	LogLocation = location
+
+	// set up logger, once the log directory is known
+	if err := SetupLogger(config.LogDir); err != nil {
+		return nil, err
+	}
+
+	if config.EventsFile {
+		if err := OpenEventsFile(); err != nil {
+			return nil, err
+		}
+	}
+
+	// switch the log file format if needed, before applying the log level
+	switch config.GetLogFormat() {
+	case LogFormatText:
+	case LogFormatJson:
+		ConfigureLoggers(LogFormatJson)
+	default:
+		return nil, fmt.Errorf("log_format must be either text or json, got %q", config.LogFormat)
+	}
+
+	// apply the log level
+	switch config.GetLogLevel() {
+	case "debug", "info", "warn", "error":
+		level, _ := log.ParseLevel(config.GetLogLevel())
+		log.SetLevel(level)
+	default:
+		return nil, fmt.Errorf("log_level must be one of debug, info, warn or error, got %q", config.LogLevel)
+	}
+
+	// verify the config values, before any of them is used
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.PrioFee != 0 {
+		log.Warn("prio_fee and -prio-fee are deprecated, use compute_unit_price_microlamports or -cu-price instead", "cu_price", config.GetComputeUnitPrice())
+	}
+
+	// verify the private keys are valid
+	if err := VerifyPrivateKeys(config); err != nil {
+		return nil, err
+	}
+
+	// route the connections through the proxy, before any of them is made
+	if config.ProxyUrl != "" {
+		if err := ApplyProxy(config.ProxyUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	// transactions sent after the blockhash expires will never land
+	if config.GetBlockhashRefreshInterval() > 60*time.Second {
+		log.Warn("Blockhash refresh interval exceeds the blockhash lifetime (~60s), some transactions may not land", "interval", config.GetBlockhashRefreshInterval())
+	}
+
+	TestStartTime = time.Now()
+	PrintHeader()
+
+	// verify the urls of each endpoint are on the same cluster
+	if err := VerifyClusters(ctx); err != nil {
+		return nil, err
+	}
+
+	// the handshakes are timed apart from the transactions
+	MeasureConnections(ctx)
+
+	// suggest a priority fee if none is set, before the balance check accounts for it
+	if config.SplitMode != SplitModeListenOnly {
+		SuggestPriorityFee(ctx)
+	}
+
+	// verify test wallet balance, nothing is sent in listen only mode
+	if config.SplitMode != SplitModeListenOnly {
+		// fund the test wallets first on devnet or a local test validator
+		if config.AutoFund {
+			if err := AutoFund(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := AssertSufficientBalance(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// expose the live metrics if enabled
+	if config.MetricsAddr != "" {
+		StartMetricsServer(config.MetricsAddr)
+	}
+
+	// trace the runs and their transactions if enabled
+	if config.OtelEndpoint != "" {
+		if err := StartTracing(ctx, config.OtelEndpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	// the missing credentials of the sink must not wait for the end of the test
+	sink, err := NewSink(config.ResultsOutput)
+	if err != nil {
+		return nil, err
+	}
+	ResultsSink = sink
+
+	// the records are written as they land, instead of being kept until the end of the test
+	if config.StreamingStats {
+		if err := OpenTxRecordsFile(); err != nil {
+			return nil, err
+		}
+	}
+
+	// run the same workload against each endpoint, one after the other,
+	// as many times as requested
+runs:
+	for run := uint64(1); run <= config.GetRepeat(); run++ {
+		runID := TestID
+
+		// each run gets its own id, so that late landings of a run aren't counted in the next one
+		if run > 1 {
+			log.Info("Pausing before the next run", "run", fmt.Sprintf("%d/%d", run, config.GetRepeat()), "delay", RepeatDelay)
+
+			select {
+			case <-ctx.Done():
+				break runs
+			case <-time.After(RepeatDelay):
+			}
+
+			runID = NewTestID()
+		}
+
+		for _, endpoint := range config.GetEndpoints() {
+			// skip the remaining endpoints once cancelled
+			if ctx.Err() != nil {
+				break runs
+			}
+
+			// the run in progress stops once the context is cancelled, its results are still saved
+			CurrentBenchmark = NewBenchmark(ctx, endpoint, runID, run)
+			if err := CurrentBenchmark.Run(); err != nil {
+				return abortTest(fmt.Errorf("error starting the run of %s: %w", CurrentBenchmark.Label(), err))
+			}
+			Benchmarks = append(Benchmarks, CurrentBenchmark)
+
+			CurrentBenchmark.PrintSummary()
+		}
+	}
+
+	return Finish(), nil
+}
+
+// abortTest ends the test after a failed run, the results of the runs completed before it are saved
+func abortTest(err error) (*Results, error) {
+	if len(Benchmarks) == 0 {
+		StopMetricsServer()
+		StopTracing()
+		return nil, err
+	}
+
+	return Finish(), err
+}
+
+// ResetState clears the state left by a previous test in the same process,
+// and closes its log and events files
+func ResetState() {
+	if LogFile != nil {
+		LogFile.Close()
+		LogFile = nil
+	}
+
+	if EventsFile != nil {
+		EventsFile.Close()
+		EventsFile = nil
+	}
+
+	if TxRecordsFile != nil {
+		CloseTxRecordsFile()
+	}
+
+	GlobalConfig = nil
+	TestAccount = nil
+	TestAccounts = nil
+	Benchmarks = nil
+	CurrentBenchmark = nil
+	finishOnce = sync.Once{}
+	finalResults = nil
+	TestEndTime = time.Time{}
+	LogLocation = time.UTC
+	EndpointConnectionTimes = make(map[string]ConnectionTimes)
+	ResultsSink = nil
+	MetricsServer = nil
+	Proxy = nil
+	proxyHttpClient = nil
+	TracerProvider = nil
+	Tracer = nil
+}
+
+// PrintHeader logs the settings of the test
+func PrintHeader() {
+	HeaderLogger.Printf("Date                : %s", TestStartTime.UTC().Format(time.RFC1123))
+	for _, wallet := range TestAccounts {
+		HeaderLogger.Printf("Test Wallet         : %s", wallet.PublicKey().String())
+	}
+	if GlobalConfig.TestID != "" {
+		HeaderLogger.Printf("Starting Test ID    : %s %s", TestID, ValueSource("test-id"))
+	} else {
+		HeaderLogger.Printf("Starting Test ID    : %s", TestID)
+	}
+	HeaderLogger.Printf("Config File         : %s", ConfigSource)
+	if GlobalConfig.GetRepeat() > 1 {
+		HeaderLogger.Printf("Repeat              : %d runs", GlobalConfig.GetRepeat())
+	}
+	if GlobalConfig.DryRun {
+		HeaderLogger.Printf("Dry Run             : yes, no transaction will be sent %s", ValueSource("dry-run"))
+	}
+	for _, endpoint := range GlobalConfig.GetEndpoints() {
+		if len(GlobalConfig.GetEndpoints()) > 1 {
+			HeaderLogger.Printf("Endpoint            : %s", endpoint.GetLabel())
+		}
+		HeaderLogger.Printf("RPC URL             : %s %s", endpoint.RpcUrl, ValueSource("rpc-url"))
+		HeaderLogger.Printf("WS URL              : %s", endpoint.GetWsUrl())
+		if GlobalConfig.GetSendMode() != SendModeJito {
+			for _, sendUrl := range endpoint.GetSendUrls() {
+				HeaderLogger.Printf("RPC Send URL        : %s", sendUrl)
+			}
+		}
+	}
+	if GlobalConfig.GetSendMode() == SendModeJito {
+		HeaderLogger.Printf("Jito Block Engine   : %s", GlobalConfig.JitoUrl)
+		HeaderLogger.Printf("Jito Tip            : %d Lamports (%.9f SOL)", GlobalConfig.JitoTip, float64(GlobalConfig.JitoTip)/float64(solana.LAMPORTS_PER_SOL))
+	}
+	if GlobalConfig.Duration > 0 {
+		HeaderLogger.Printf("Test Duration       : %s", time.Duration(GlobalConfig.Duration)*time.Second)
+	} else {
+		HeaderLogger.Printf("Transaction Count   : %d %s", GlobalConfig.TxCount, ValueSource("tx-count"))
+	}
+	if GlobalConfig.WarmupTxCount > 0 {
+		HeaderLogger.Printf("Warmup Tx Count     : %d", GlobalConfig.WarmupTxCount)
+	}
+	if GlobalConfig.StartDelay != nil {
+		HeaderLogger.Printf("Start Delay         : %s", time.Duration(*GlobalConfig.StartDelay*float64(time.Second)))
+	}
+	if GlobalConfig.IsRamp() {
+		HeaderLogger.Printf("Rate Ramp           : %d to %d tx/s in %d steps", GlobalConfig.RateStart, GlobalConfig.RateEnd, GlobalConfig.GetRateSteps())
+	} else {
+		HeaderLogger.Printf("Rate Limit          : %d %s", GlobalConfig.RateLimit, ValueSource("rate-limit"))
+	}
+	if GlobalConfig.GetBurst() != GlobalConfig.GetRateLimit() {
+		HeaderLogger.Printf("Burst               : %d", GlobalConfig.GetBurst())
+	}
+	if GlobalConfig.SendWindow > 0 {
+		HeaderLogger.Printf("Send Window         : %s", GlobalConfig.GetSendWindow())
+	}
+	if GlobalConfig.SendBatchSize > 1 {
+		HeaderLogger.Printf("Send Batch Size     : %d", GlobalConfig.SendBatchSize)
+	}
+	if GlobalConfig.Workers > 0 {
+		HeaderLogger.Printf("Send Workers        : %d", GlobalConfig.Workers)
+	}
+	if GlobalConfig.SplitMode != "" {
+		HeaderLogger.Printf("Split Mode          : %s", GlobalConfig.SplitMode)
+	}
+	if GlobalConfig.StreamingStats {
+		HeaderLogger.Printf("Streaming Stats     : within %.0f%%", SketchRelativeError*100)
+	}
+	switch {
+	case GlobalConfig.GetPrioFeeMode() == PrioFeeModeDynamic:
+		HeaderLogger.Printf("Priority Fee/CU     : dynamic (p%v of recent fees)", GlobalConfig.GetPrioFeePercentile())
+	case GlobalConfig.IsFeeSweep():
+		HeaderLogger.Printf("Compute Unit Price  : %d to %d micro-lamports in %d steps", GlobalConfig.FeeMin, GlobalConfig.FeeMax, GlobalConfig.GetFeeSteps())
+	default:
+		source := ValueSource("cu-price")
+		if SetFlags["prio-fee"] {
+			source = ValueSource("prio-fee")
+		}
+		HeaderLogger.Printf("Compute Unit Price  : %s %s", FormatComputeUnitPrice(GlobalConfig.GetComputeUnitPrice()), source)
+	}
+	HeaderLogger.Printf("Compute Unit Limit  : %d", GlobalConfig.GetComputeUnitLimit())
+	HeaderLogger.Printf("Commitment          : %s", GlobalConfig.GetCommitment())
+	HeaderLogger.Printf("Blockhash Commitment: %s", GlobalConfig.GetBlockhashCommitment())
+	HeaderLogger.Printf("Confirm Mode        : %s", GlobalConfig.GetConfirmMode())
+	if GlobalConfig.TrackAllCommitments {
+		HeaderLogger.Printf("Commitment Lifecycle: polled every %s", LifecyclePollInterval)
+	}
+	switch GlobalConfig.GetConfirmSource() {
+	case ConfirmSourceGeyser:
+		HeaderLogger.Printf("Confirm Source      : %s (%s)", GlobalConfig.GetConfirmSource(), RedactUrl(GlobalConfig.GeyserUrl))
+	case ConfirmSourceBlock:
+		HeaderLogger.Printf("Confirm Source      : %s", GlobalConfig.GetConfirmSource())
+	case ConfirmSourceSignature:
+		HeaderLogger.Printf("Confirm Source      : %s (max %d subscriptions)", GlobalConfig.GetConfirmSource(), GlobalConfig.GetMaxSignatureSubscriptions())
+	}
+	if GlobalConfig.CrossCheckWsUrl != "" {
+		HeaderLogger.Printf("Cross-Check WS URL  : %s", RedactUrl(GlobalConfig.CrossCheckWsUrl))
+	}
+	if GlobalConfig.ControlRate > 0 {
+		HeaderLogger.Printf("Control Stream      : %d tx/s at %s", GlobalConfig.ControlRate, FormatComputeUnitPrice(GlobalConfig.ControlCuPrice))
+	}
+	HeaderLogger.Printf("Node Retries        : %d", GlobalConfig.NodeRetries)
+	if GlobalConfig.MaxResends > 0 {
+		HeaderLogger.Printf("Max Resends         : %d (once expired)", GlobalConfig.MaxResends)
+	}
+	if !GlobalConfig.GetSkipPreflight() {
+		preflightCommitment := GlobalConfig.PreflightCommitment
+		if preflightCommitment == "" {
+			preflightCommitment = "rpc default"
+		}
+		HeaderLogger.Printf("Preflight           : enabled (%s)", preflightCommitment)
+	}
+	HeaderLogger.Printf("Send Timeout        : %s", GlobalConfig.GetSendTimeout())
+	if GlobalConfig.MinLandingRate > 0 {
+		HeaderLogger.Printf("Min Landing Rate    : %.1f%%", GlobalConfig.MinLandingRate*100)
+	}
+	if GlobalConfig.EarlyAbortWindow > 0 {
+		HeaderLogger.Printf("Early Abort         : below %.1f%% of the first %d txs", GlobalConfig.EarlyAbortThreshold*100, GlobalConfig.EarlyAbortWindow)
+	}
+	switch GlobalConfig.GetWorkload() {
+	case WorkloadMemo:
+		HeaderLogger.Printf("Memo Template       : %s", GlobalConfig.GetMemoTemplate())
+		if GlobalConfig.MemoProgramId != "" {
+			HeaderLogger.Printf("Memo Program        : %s", GlobalConfig.MemoProgramId)
+		}
+	case WorkloadTransfer:
+		HeaderLogger.Printf("Workload            : %s", GlobalConfig.GetWorkload())
+	case WorkloadProgram:
+		HeaderLogger.Printf("Workload            : %s (%s, %d bytes)", GlobalConfig.GetWorkload(), GlobalConfig.WorkloadProgramId, GlobalConfig.GetWorkloadDataSize())
+	}
+	HeaderLogger.Printf("Tx Version          : %s", GlobalConfig.GetTxVersion())
+	if GlobalConfig.LookupTable != "" {
+		HeaderLogger.Printf("Lookup Table        : %s", GlobalConfig.LookupTable)
+	}
+	if GlobalConfig.ProxyUrl != "" {
+		HeaderLogger.Printf("Proxy URL           : %s", RedactUrl(GlobalConfig.ProxyUrl))
+	}
+	if len(GlobalConfig.RpcHeaders) > 0 {
+		// only the names are shown, the values usually hold the credentials
+		names := slices.Sorted(maps.Keys(GlobalConfig.RpcHeaders))
+		HeaderLogger.Printf("RPC Headers         : %s", strings.Join(names, ", "))
+	}
+	if GlobalConfig.MetricsAddr != "" {
+		HeaderLogger.Printf("Metrics Address     : %s", GlobalConfig.MetricsAddr)
+	}
+	if GlobalConfig.OtelEndpoint != "" {
+		HeaderLogger.Printf("OTel Endpoint       : %s", RedactUrl(GlobalConfig.OtelEndpoint))
+	}
+	if GlobalConfig.ResultsOutput != "" {
+		HeaderLogger.Printf("Results Output      : %s", RedactUrl(GlobalConfig.ResultsOutput))
+	}
+	if Baseline != nil {
+		HeaderLogger.Printf("Baseline            : %s (Test ID %s)", BaselineFile, Baseline.TestID)
+	}
+	HeaderLogger.Printf("")
+}
@@ -0,0 +1,223 @@
+package bench
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// SignatureWatcher is implemented by the listeners that follow each transaction once it's sent
+type SignatureWatcher interface {
+	Watch(sig solana.Signature)
+}
+
+// SignatureListener follows each sent transaction through its own signature subscription,
+// the subscriptions are capped, the transactions sent past the cap wait for a free one
+type SignatureListener struct {
+	Bench     *Benchmark
+	Listening bool
+
+	mu     sync.Mutex
+	Client *ws.Client
+
+	// the signatures being watched on the current connection
+	watched map[solana.Signature]bool
+
+	// a slot per open subscription, to cap their number
+	slots chan struct{}
+
+	// signaled when a subscription failed, and closed when the connection is replaced
+	lost   chan struct{}
+	closed chan struct{}
+
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func NewSignatureListener(bench *Benchmark) *SignatureListener {
+	return &SignatureListener{
+		Bench:   bench,
+		watched: make(map[solana.Signature]bool),
+		slots:   make(chan struct{}, GlobalConfig.GetMaxSignatureSubscriptions()),
+		lost:    make(chan struct{}, 1),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Connect connects to the websocket, the listener is then listening until stopped
+func (l *SignatureListener) Connect() error {
+	if err := l.connect(); err != nil {
+		return err
+	}
+
+	l.Listening = true
+	return nil
+}
+
+func (l *SignatureListener) IsListening() bool {
+	return l.Listening
+}
+
+// connect (re)connects to the websocket, the subscriptions of the previous connection are dropped
+func (l *SignatureListener) connect() error {
+	wsClient, err := ConnectWs(l.Bench.ctx, l.Bench.Endpoint.GetWsUrl())
+	if err != nil {
+		return fmt.Errorf("error connecting to websocket: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// close the previous connection if any
+	if l.Client != nil {
+		close(l.closed)
+		l.Client.Close()
+	}
+
+	l.Client = wsClient
+	l.closed = make(chan struct{})
+	l.watched = make(map[solana.Signature]bool)
+
+	return nil
+}
+
+// Watch subscribes to the signature of a sent transaction, once a subscription is available
+func (l *SignatureListener) Watch(sig solana.Signature) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.Listening || l.watched[sig] {
+		return
+	}
+
+	l.watched[sig] = true
+	go l.watch(sig, l.Client, l.closed)
+}
+
+// watch waits for the notification of the signature, until the subscription fails,
+// the connection is replaced or the listener is stopped
+func (l *SignatureListener) watch(sig solana.Signature, wsClient *ws.Client, closed <-chan struct{}) {
+	select {
+	case l.slots <- struct{}{}:
+	case <-closed:
+		return
+	case <-l.stopped:
+		return
+	}
+
+	// free the subscription for the next transaction
+	defer func() { <-l.slots }()
+	defer l.forget(sig, closed)
+
+	sub, err := wsClient.SignatureSubscribe(sig, GlobalConfig.GetCommitment())
+	if err != nil {
+		l.fail(closed, err)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case got := <-sub.Response():
+		// the failed transactions are notified too, but didn't land
+		if got == nil || got.Value.Err != nil {
+			return
+		}
+
+		l.Bench.HandleLanding(sig, got.Context.Slot)
+	case err := <-sub.Err():
+		l.fail(closed, err)
+	case <-closed:
+	case <-l.stopped:
+	}
+}
+
+// forget removes the signature from the watched ones, unless the connection was replaced meanwhile
+func (l *SignatureListener) forget(sig solana.Signature, closed <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if closed == l.closed {
+		delete(l.watched, sig)
+	}
+}
+
+// fail reports the loss of a subscription of the current connection
+func (l *SignatureListener) fail(closed <-chan struct{}, err error) {
+	select {
+	case <-closed:
+		return
+	case <-l.stopped:
+		return
+	default:
+	}
+
+	select {
+	case l.lost <- struct{}{}:
+		log.Error("Websocket connection lost", "err", err)
+	default:
+	}
+}
+
+// Reconnect tries to restore a broken websocket connection
+func (l *SignatureListener) Reconnect() bool {
+	return l.Bench.Reconnect("websocket", l.IsListening, l.connect)
+}
+
+// Start watches the connection until stopped, the listener must be connected beforehand
+func (l *SignatureListener) Start() {
+	defer l.Bench.wg.Done()
+
+	log.Info("Listening for signatures...", "endpoint", l.Bench.Endpoint.GetLabel(), "max_subscriptions", GlobalConfig.GetMaxSignatureSubscriptions())
+
+	for l.Listening {
+		select {
+		case <-l.lost:
+		case <-l.stopped:
+			continue
+		}
+
+		if !l.Reconnect() {
+			// in both mode, the poller keeps tracking the landings
+			log.Error("Unable to reconnect to websocket, giving up")
+			l.Stop()
+			break
+		}
+
+		// the subscriptions of the previous connection may have failed together
+		select {
+		case <-l.lost:
+		default:
+		}
+
+		if l.Bench.AllTransactionsLanded() {
+			l.Bench.Stop()
+			continue
+		}
+
+		// the landings were backfilled, watch the transactions still pending on the new connection
+		for _, sig := range l.Bench.UnlandedSignatures() {
+			l.Watch(sig)
+		}
+	}
+
+	log.Info("Stopping listening for signatures...")
+}
+
+func (l *SignatureListener) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.Listening {
+		return
+	}
+
+	l.Listening = false
+	l.stopOnce.Do(func() { close(l.stopped) })
+
+	if l.Client != nil {
+		l.Client.Close()
+	}
+}
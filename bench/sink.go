@@ -0,0 +1,296 @@
+package bench
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// the schemes of results_output, the results files are saved next to the log file by default
+	ResultsSchemeFile  = "file"
+	ResultsSchemeHttp  = "http"
+	ResultsSchemeHttps = "https"
+	ResultsSchemeS3    = "s3"
+
+	// the upload of a results file is retried with a growing delay
+	MaxUploadAttempts = 3
+	UploadRetryDelay  = time.Second
+	UploadTimeout     = 30 * time.Second
+
+	// the region of the bucket when AWS_REGION isn't set
+	DefaultS3Region = "us-east-1"
+)
+
+// the sink the results files are saved to, only set with results_output
+var ResultsSink Sink
+
+// Sink stores the results files, i.e. the json results and the csv records,
+// the log and events files are always written locally as the test goes
+type Sink interface {
+	// Write saves the file under the given name, and returns where it was saved
+	Write(name string, contentType string, data []byte) (string, error)
+}
+
+// NewSink returns the sink of the results_output url, nil if empty
+func NewSink(output string) (Sink, error) {
+	if output == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing results_output: %w", err)
+	}
+
+	switch u.Scheme {
+	case ResultsSchemeFile:
+		return &FileSink{Dir: u.Path}, nil
+	case ResultsSchemeHttp, ResultsSchemeHttps:
+		return &HttpSink{BaseUrl: strings.TrimSuffix(output, "/")}, nil
+	case ResultsSchemeS3:
+		return NewS3Sink(u.Host, strings.Trim(u.Path, "/"))
+	}
+
+	return nil, fmt.Errorf("unsupported results_output scheme %q", u.Scheme)
+}
+
+// SaveResultsFile saves the results file to the sink if set, or else next to the log file,
+// where it's also saved if the sink failed so that the results of the test are never lost,
+// it returns where the file was saved
+func SaveResultsFile(fileName string, contentType string, data []byte) (string, error) {
+	if ResultsSink != nil {
+		location, err := ResultsSink.Write(filepath.Base(fileName), contentType, data)
+		if err == nil {
+			return location, nil
+		}
+
+		log.Errorf("error saving %s to results_output, saving it next to the log file instead: %v", filepath.Base(fileName), err)
+	}
+
+	return fileName, os.WriteFile(fileName, data, 0644)
+}
+
+// FileSink saves the files to a local directory, created if needed
+type FileSink struct {
+	Dir string
+}
+
+func (s *FileSink) Write(name string, contentType string, data []byte) (string, error) {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating results directory: %w", err)
+	}
+
+	fileName := filepath.Join(s.Dir, name)
+	return fileName, os.WriteFile(fileName, data, 0644)
+}
+
+// HttpSink uploads the files with an http PUT request, to the base url followed by the file name
+type HttpSink struct {
+	BaseUrl string
+}
+
+func (s *HttpSink) Write(name string, contentType string, data []byte) (string, error) {
+	target := s.BaseUrl + "/" + url.PathEscape(name)
+
+	err := upload(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, target, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		return req, nil
+	})
+
+	return RedactUrl(target), err
+}
+
+// S3Sink uploads the files to an S3-compatible bucket, under the key prefix if set,
+// the credentials, region and endpoint are read from the standard AWS environment variables
+type S3Sink struct {
+	Bucket string
+	Prefix string
+
+	// the endpoint of an S3-compatible storage, the buckets are then addressed by path
+	Endpoint string
+	Region   string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// NewS3Sink returns the sink of the bucket, it fails if the credentials aren't set
+func NewS3Sink(bucket string, prefix string) (*S3Sink, error) {
+	s := &S3Sink{
+		Bucket:          bucket,
+		Prefix:          prefix,
+		Endpoint:        strings.TrimSuffix(os.Getenv("AWS_ENDPOINT_URL"), "/"),
+		Region:          os.Getenv("AWS_REGION"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+
+	if s.AccessKeyID == "" || s.SecretAccessKey == "" {
+		return nil, errors.New("an s3 results_output requires the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables")
+	}
+
+	if s.Region == "" {
+		s.Region = DefaultS3Region
+	}
+
+	return s, nil
+}
+
+// ObjectUrl returns the url of the object of the file, by path on a custom endpoint, by virtual host on AWS
+func (s *S3Sink) ObjectUrl(name string) string {
+	key := name
+	if s.Prefix != "" {
+		key = s.Prefix + "/" + name
+	}
+
+	if s.Endpoint != "" {
+		return s.Endpoint + "/" + s.Bucket + "/" + escapeS3Key(key)
+	}
+
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.Bucket, s.Region, escapeS3Key(key))
+}
+
+func (s *S3Sink) Write(name string, contentType string, data []byte) (string, error) {
+	target := s.ObjectUrl(name)
+
+	err := upload(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, target, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		s.sign(req, data, time.Now().UTC())
+		return req, nil
+	})
+
+	return fmt.Sprintf("s3://%s/%s", s.Bucket, path.Join(s.Prefix, name)), err
+}
+
+// sign adds the AWS signature version 4 of the request to its headers
+func (s *S3Sink) sign(req *http.Request, payload []byte, now time.Time) {
+	payloadHash := sha256Hex(payload)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	// the signed headers are sorted by their lowercase name
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{req.Method, req.URL.EscapedPath(), req.URL.RawQuery, canonicalHeaders, signedHeaders, payloadHash}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.Region)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSha256(key, s.Region)
+	key = hmacSha256(key, "s3")
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// escapeS3Key escapes the key as in the canonical uri of the signature, every byte but the unreserved ones and "/"
+func escapeS3Key(key string) string {
+	var out strings.Builder
+	for _, c := range []byte(key) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~', c == '/':
+			out.WriteByte(c)
+		default:
+			fmt.Fprintf(&out, "%%%02X", c)
+		}
+	}
+
+	return out.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// upload sends the request built by newRequest, which is rebuilt for each attempt,
+// the failed attempts are retried with a growing delay
+func upload(newRequest func() (*http.Request, error)) error {
+	client := &http.Client{Timeout: UploadTimeout}
+
+	delay := UploadRetryDelay
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return err
+		}
+
+		err = doUpload(client, req)
+		if err == nil || attempt >= MaxUploadAttempts {
+			return err
+		}
+
+		log.Warn("Unable to upload the results file, retrying", "attempt", fmt.Sprintf("%d/%d", attempt, MaxUploadAttempts), "delay", delay, "err", err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func doUpload(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package bench
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+// Sketch summarizes a stream of non-negative values in bounded memory, the values are counted in
+// logarithmic buckets so that the quantiles are within SketchRelativeError of the exact ones,
+// the count, mean, min and max are exact
+type Sketch struct {
+	logGamma float64
+	buckets  map[int]uint64
+	zeros    uint64
+
+	count uint64
+	sum   float64
+	sumSq float64
+	min   float64
+	max   float64
+}
+
+func NewSketch() *Sketch {
+	gamma := (1 + SketchRelativeError) / (1 - SketchRelativeError)
+
+	return &Sketch{
+		logGamma: math.Log(gamma),
+		buckets:  make(map[int]uint64),
+	}
+}
+
+// Add records a value, the negative ones are recorded as 0
+func (s *Sketch) Add(value float64) {
+	value = max(value, 0)
+
+	if s.count == 0 {
+		s.min, s.max = value, value
+	}
+
+	s.count += 1
+	s.sum += value
+	s.sumSq += value * value
+	s.min = min(s.min, value)
+	s.max = max(s.max, value)
+
+	// the values below 1 are all counted as 0, they're nanoseconds or slots
+	if value < 1 {
+		s.zeros += 1
+		return
+	}
+
+	s.buckets[int(math.Ceil(math.Log(value)/s.logGamma))] += 1
+}
+
+// Merge adds the values of the other sketch, both use the same relative error
+func (s *Sketch) Merge(other *Sketch) {
+	if other.count == 0 {
+		return
+	}
+
+	if s.count == 0 {
+		s.min, s.max = other.min, other.max
+	}
+
+	s.count += other.count
+	s.sum += other.sum
+	s.sumSq += other.sumSq
+	s.min = min(s.min, other.min)
+	s.max = max(s.max, other.max)
+	s.zeros += other.zeros
+
+	for index, count := range other.buckets {
+		s.buckets[index] += count
+	}
+}
+
+func (s *Sketch) Count() uint64 {
+	return s.count
+}
+
+func (s *Sketch) Mean() float64 {
+	if s.count == 0 {
+		return 0
+	}
+
+	return s.sum / float64(s.count)
+}
+
+// StdDev returns the population standard deviation, like the exact stats
+func (s *Sketch) StdDev() float64 {
+	if s.count == 0 {
+		return 0
+	}
+
+	mean := s.Mean()
+	return math.Sqrt(max(s.sumSq/float64(s.count)-mean*mean, 0))
+}
+
+// Quantile returns the estimated value below which the given fraction of the values are
+func (s *Sketch) Quantile(q float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+
+	// the rank of the value, 0 based
+	rank := uint64(math.Ceil(q*float64(s.count))) - 1
+	if q <= 0 {
+		rank = 0
+	}
+
+	if rank < s.zeros {
+		return s.min
+	}
+
+	seen := s.zeros
+	for _, index := range slices.Sorted(maps.Keys(s.buckets)) {
+		seen += s.buckets[index]
+		if seen > rank {
+			// the middle of the bucket, in relative terms
+			value := 2 * math.Exp(float64(index)*s.logGamma) / (math.Exp(s.logGamma) + 1)
+			return min(max(value, s.min), s.max)
+		}
+	}
+
+	return s.max
+}
+
+// LandingStats returns the landing time stats of a sketch of deltas in nanoseconds
+func (s *Sketch) LandingStats() LandingStats {
+	return LandingStats{
+		Min:    time.Duration(s.min),
+		Max:    time.Duration(s.max),
+		Avg:    time.Duration(s.Mean()),
+		Median: time.Duration(s.Quantile(0.50)),
+		P90:    time.Duration(s.Quantile(0.90)),
+		P95:    time.Duration(s.Quantile(0.95)),
+		P99:    time.Duration(s.Quantile(0.99)),
+		StdDev: time.Duration(s.StdDev()),
+		IQR:    time.Duration(s.Quantile(0.75) - s.Quantile(0.25)),
+	}
+}
+
+// SlotStats returns the slot stats of a sketch of slot distances
+func (s *Sketch) SlotStats() SlotStats {
+	return SlotStats{
+		Min:    s.min,
+		Max:    s.max,
+		Avg:    s.Mean(),
+		Median: s.Quantile(0.50),
+		P90:    s.Quantile(0.90),
+		P95:    s.Quantile(0.95),
+		P99:    s.Quantile(0.99),
+	}
+}
+
+// recordDelta records the landing time of a transaction, in the sketch with streaming_stats,
+// it must be called with the lock held
+func (b *Benchmark) recordDelta(delta time.Duration) {
+	if b.DeltaSketch == nil {
+		b.TxDeltas = append(b.TxDeltas, delta)
+		return
+	}
+
+	b.DeltaSketch.Add(float64(delta.Nanoseconds()))
+	b.LatencyCounts[min(int(delta/GlobalConfig.GetHistogramBucket()), MaxHistogramBuckets-1)] += 1
+}
+
+// LandingTimeStats returns the landing time stats, false if no landing time was recorded
+func (b *Benchmark) LandingTimeStats() (LandingStats, bool) {
+	if b.DeltaSketch != nil {
+		return b.DeltaSketch.LandingStats(), b.DeltaSketch.Count() > 0
+	}
+
+	if len(b.TxDeltas) == 0 {
+		return LandingStats{}, false
+	}
+
+	return ComputeLandingStats(b.TxDeltas), true
+}
+
+// LandingTimePercentiles returns the configured percentiles of the landing times, in their order
+func (b *Benchmark) LandingTimePercentiles() []time.Duration {
+	if b.DeltaSketch == nil {
+		return ComputePercentiles(b.TxDeltas, GlobalConfig.GetPercentiles())
+	}
+
+	out := []time.Duration{}
+	for _, p := range GlobalConfig.GetPercentiles() {
+		out = append(out, time.Duration(b.DeltaSketch.Quantile(p/100)))
+	}
+
+	return out
+}
+
+// SendLatencyStats returns the stats of the time the RPC took to accept the transactions,
+// false if none was sent
+func (b *Benchmark) SendLatencyStats() (LandingStats, bool) {
+	if b.SendLatencySketch != nil {
+		return b.SendLatencySketch.LandingStats(), b.SendLatencySketch.Count() > 0
+	}
+
+	if len(b.TxSendLatencies) == 0 {
+		return LandingStats{}, false
+	}
+
+	return ComputeLandingStats(b.TxSendLatencies), true
+}
+
+// recordSendLatency records the time the RPC took to accept a transaction, it must be called with the lock held
+func (b *Benchmark) recordSendLatency(latency time.Duration) {
+	if b.SendLatencySketch != nil {
+		b.SendLatencySketch.Add(float64(latency.Nanoseconds()))
+		return
+	}
+
+	b.TxSendLatencies = append(b.TxSendLatencies, latency)
+}
+
+// SlotDistanceStats returns the stats of the slots between the send slots and the landing slots,
+// false if none was recorded
+func (b *Benchmark) SlotDistanceStats() (SlotStats, bool) {
+	if b.SlotDistanceSketch != nil {
+		return b.SlotDistanceSketch.SlotStats(), b.SlotDistanceSketch.Count() > 0
+	}
+
+	if len(b.TxSlotDistances) == 0 {
+		return SlotStats{}, false
+	}
+
+	return ComputeSlotStats(b.TxSlotDistances), true
+}
+
+// InclusionOffsetStats returns the stats of the slots between the blockhash slots and the landing slots,
+// false if none was recorded
+func (b *Benchmark) InclusionOffsetStats() (SlotStats, bool) {
+	if b.InclusionOffsetSketch != nil {
+		return b.InclusionOffsetSketch.SlotStats(), b.InclusionOffsetSketch.Count() > 0
+	}
+
+	if len(b.TxInclusionOffsets) == 0 {
+		return SlotStats{}, false
+	}
+
+	return ComputeSlotStats(b.TxInclusionOffsets), true
+}
+
+// latencyCounts returns the number of landing times in each histogram bucket
+func (b *Benchmark) latencyCounts() []uint64 {
+	if b.DeltaSketch != nil {
+		return b.LatencyCounts
+	}
+
+	width := GlobalConfig.GetHistogramBucket()
+
+	counts := make([]uint64, MaxHistogramBuckets)
+	for _, delta := range b.TxDeltas {
+		counts[min(int(delta/width), MaxHistogramBuckets-1)] += 1
+	}
+
+	return counts
+}
@@ -0,0 +1,119 @@
+package bench
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// checkSketch compares the stats of the sketch with the exact stats of the deltas
+func checkSketch(t *testing.T, s *Sketch, deltas []time.Duration) {
+	t.Helper()
+
+	if s.Count() != uint64(len(deltas)) {
+		t.Errorf("count = %d, want %d", s.Count(), len(deltas))
+	}
+
+	got, want := s.LandingStats(), ComputeLandingStats(deltas)
+	if got.Min != want.Min || got.Max != want.Max || got.Avg != want.Avg {
+		t.Errorf("min/max/avg = %s/%s/%s, want %s/%s/%s", got.Min, got.Max, got.Avg, want.Min, want.Max, want.Avg)
+	}
+
+	percentiles := []float64{50, 90, 95, 99}
+	exact := ComputePercentiles(deltas, percentiles)
+	for i, p := range percentiles {
+		estimate := time.Duration(s.Quantile(p / 100))
+		if math.Abs(float64(estimate-exact[i])) > SketchRelativeError*float64(exact[i]) {
+			t.Errorf("%s = %s, want %s within %.0f%%", FormatPercentile(p), estimate, exact[i], SketchRelativeError*100)
+		}
+	}
+
+	if got.Median != time.Duration(s.Quantile(0.50)) || got.P99 != time.Duration(s.Quantile(0.99)) {
+		t.Errorf("median/P99 = %s/%s, want the quantiles of the sketch", got.Median, got.P99)
+	}
+}
+
+func TestSketch(t *testing.T) {
+	// the exact percentiles interpolate between the neighboring values, so the deltas are dense
+	// enough for the interpolation to stay well below the relative error
+	uniform := []time.Duration{}
+	for i := 1; i <= 1000; i++ {
+		uniform = append(uniform, time.Duration(i)*time.Millisecond)
+	}
+
+	spread := []time.Duration{}
+	for i := range 3000 {
+		spread = append(spread, time.Duration(float64(time.Millisecond)*math.Pow(1.002, float64(i))))
+	}
+
+	constant := []time.Duration{}
+	for range 100 {
+		constant = append(constant, 400*time.Millisecond)
+	}
+
+	// the landings below a nanosecond are counted in the zeros bucket
+	someZeros := append(make([]time.Duration, 200), uniform...)
+	mostlyZeros := append(make([]time.Duration, 600), uniform[:400]...)
+
+	tests := []struct {
+		name   string
+		deltas []time.Duration
+	}{
+		{"single", []time.Duration{350 * time.Millisecond}},
+		{"constant", constant},
+		{"uniform", uniform},
+		{"spread", spread},
+		{"some zeros", someZeros},
+		{"mostly zeros", mostlyZeros},
+		{"all zeros", make([]time.Duration, 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSketch()
+			for _, delta := range tt.deltas {
+				s.Add(float64(delta))
+			}
+
+			checkSketch(t, s, tt.deltas)
+		})
+
+		t.Run(tt.name+" merged", func(t *testing.T) {
+			// every other delta in each sketch, merged into an empty one
+			even, odd := NewSketch(), NewSketch()
+			for i, delta := range tt.deltas {
+				if i%2 == 0 {
+					even.Add(float64(delta))
+				} else {
+					odd.Add(float64(delta))
+				}
+			}
+
+			s := NewSketch()
+			s.Merge(even)
+			s.Merge(odd)
+			s.Merge(NewSketch())
+
+			checkSketch(t, s, tt.deltas)
+		})
+	}
+}
+
+func TestSketchZeros(t *testing.T) {
+	s := NewSketch()
+	for _, value := range []float64{-5, 0, 0.5, 10} {
+		s.Add(value)
+	}
+
+	// the negative values are recorded as 0, along with the ones below 1
+	if s.zeros != 3 || s.Count() != 4 {
+		t.Errorf("zeros = %d of %d values, want 3 of 4", s.zeros, s.Count())
+	}
+
+	if q := s.Quantile(0.75); q != 0 {
+		t.Errorf("P75 = %f, want 0", q)
+	}
+	if q := s.Quantile(1); q != 10 {
+		t.Errorf("P100 = %f, want 10", q)
+	}
+}
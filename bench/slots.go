@@ -0,0 +1,87 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// SlotTracker follows the current slot through a slot subscription,
+// it's used to record the slot each transaction was sent at
+type SlotTracker struct {
+	Client       *ws.Client
+	Subscription *ws.SlotSubscription
+
+	slot    atomic.Uint64
+	running atomic.Bool
+
+	// set when the subscription failed, the slot is no longer updated
+	lost atomic.Bool
+}
+
+// NewSlotTracker subscribes to the slot updates, starting from the given slot
+func NewSlotTracker(ctx context.Context, wsUrl string, slot uint64) (*SlotTracker, error) {
+	wsClient, err := ConnectWs(ctx, wsUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to websocket: %w", err)
+	}
+
+	sub, err := wsClient.SlotSubscribe()
+	if err != nil {
+		wsClient.Close()
+		return nil, fmt.Errorf("error subscribing to slots: %w", err)
+	}
+
+	t := &SlotTracker{Client: wsClient, Subscription: sub}
+	t.slot.Store(slot)
+	t.running.Store(true)
+
+	return t, nil
+}
+
+func (t *SlotTracker) Start() {
+	for t.running.Load() {
+		got, err := t.Subscription.Recv()
+		if err != nil {
+			if t.running.Load() {
+				log.Warn("Slot subscription lost, send slots will not be updated", "err", err)
+			}
+			t.lost.Store(true)
+			return
+		}
+
+		if got == nil {
+			continue
+		}
+
+		// the notifications may arrive out of order
+		for {
+			current := t.slot.Load()
+			if got.Slot <= current || t.slot.CompareAndSwap(current, got.Slot) {
+				break
+			}
+		}
+	}
+}
+
+// Slot returns the latest known slot
+func (t *SlotTracker) Slot() uint64 {
+	return t.slot.Load()
+}
+
+// Available reports whether the slot is still being followed
+func (t *SlotTracker) Available() bool {
+	return !t.lost.Load()
+}
+
+func (t *SlotTracker) Stop() {
+	if !t.running.Swap(false) {
+		return
+	}
+
+	t.Subscription.Unsubscribe()
+	t.Client.Close()
+}
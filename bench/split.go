@@ -0,0 +1,92 @@
+package bench
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// the format of the generated test ids, a fixed test id must match it to be found in the memos
+var testIDRegexp = regexp.MustCompile(`^[0-9a-f]{8}$`)
+
+// ValidateTestID checks that the test id has the format of the generated ones
+func ValidateTestID(id string) error {
+	if !testIDRegexp.MatchString(id) {
+		return errors.New("test_id must be 8 lowercase hex characters, e.g. 1a2b3c4d")
+	}
+
+	return nil
+}
+
+// RecordListenedLanding records the landing of a transaction of the test in listen only mode,
+// the transactions aren't known beforehand, so they're recorded as they land and their send time is unknown,
+// it returns false if the transaction was already recorded
+func (b *Benchmark) RecordListenedLanding(sig solana.Signature, num uint64, slot uint64) bool {
+	b.mu.Lock()
+	if _, ok := b.TxRecords[sig]; ok {
+		b.mu.Unlock()
+		return false
+	}
+
+	now := time.Now()
+	record := &TxRecord{Signature: sig, Num: num, Landed: true, LandTime: now, Slot: slot}
+	if GlobalConfig.TrackAllCommitments {
+		record.CommitmentDeltas = make(map[rpc.CommitmentType]time.Duration)
+	}
+	b.TxRecords[sig] = record
+	b.ProcessedTransactions += 1
+	b.TxBlocks[slot] += 1
+
+	// the landed TPS is measured from the first landing
+	if b.SpamStartTime.IsZero() {
+		b.SpamStartTime = now
+	}
+	b.LastLandTime = now
+
+	LandedCounter.WithLabelValues(b.TestID, b.Endpoint.GetLabel()).Inc()
+
+	landed := b.ProcessedTransactions
+	b.mu.Unlock()
+
+	b.WriteLandedEvent(record)
+
+	log.Debug("Tx Landed", "num", num, "sig", sig.String(), "landed", landed)
+
+	// in tx count mode, the run is over once all the transactions landed
+	if GlobalConfig.Duration == 0 && landed >= GlobalConfig.TxCount {
+		b.Stop()
+	}
+
+	return true
+}
+
+// WatchListenIdle stops a listen only run once no transaction landed for as long as a blockhash is valid,
+// the run keeps listening until the first landing
+func (b *Benchmark) WatchListenIdle() {
+	idle := time.Duration(BlockhashValidBlocks+BlockhashExpiryMargin) * SlotDuration
+
+	ticker := time.NewTicker(ExpiryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopped:
+			return
+		case <-ticker.C:
+		}
+
+		b.mu.RLock()
+		last := b.LastLandTime
+		b.mu.RUnlock()
+
+		if !last.IsZero() && time.Since(last) > idle {
+			log.Info("No transaction landed recently, stopping the run", "idle", idle)
+			b.Stop()
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package bench
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/montanaflynn/stats"
+)
+
+type LandingStats struct {
+	Min    time.Duration
+	Max    time.Duration
+	Avg    time.Duration
+	Median time.Duration
+	P90    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+
+	// dispersion of the landing times, the standard deviation is the jitter
+	StdDev time.Duration
+	IQR    time.Duration
+}
+
+func ComputeLandingStats(deltas []time.Duration) LandingStats {
+	var landingTimes []float64
+	for _, v := range deltas {
+		landingTimes = append(landingTimes, float64(v.Nanoseconds()))
+	}
+
+	minDelta, _ := stats.Min(landingTimes)
+	maxDelta, _ := stats.Max(landingTimes)
+	avg, _ := stats.Mean(landingTimes)
+	median, _ := stats.Median(landingTimes)
+	p90, _ := stats.Percentile(landingTimes, 90)
+	p95, _ := stats.Percentile(landingTimes, 95)
+	p99, _ := stats.Percentile(landingTimes, 99)
+	stdDev, _ := stats.StandardDeviation(landingTimes)
+	iqr, _ := stats.InterQuartileRange(landingTimes)
+
+	return LandingStats{
+		Min:    time.Duration(minDelta),
+		Max:    time.Duration(maxDelta),
+		Avg:    time.Duration(avg),
+		Median: time.Duration(median),
+		P90:    time.Duration(p90),
+		P95:    time.Duration(p95),
+		P99:    time.Duration(p99),
+		StdDev: time.Duration(stdDev),
+		IQR:    time.Duration(iqr),
+	}
+}
+
+// ComputePercentiles returns the given percentiles of the landing times, in the same order
+func ComputePercentiles(deltas []time.Duration, percentiles []float64) []time.Duration {
+	var landingTimes []float64
+	for _, v := range deltas {
+		landingTimes = append(landingTimes, float64(v.Nanoseconds()))
+	}
+
+	out := []time.Duration{}
+	for _, p := range percentiles {
+		value, _ := stats.Percentile(landingTimes, p)
+		out = append(out, time.Duration(value))
+	}
+
+	return out
+}
+
+// FormatPercentile returns the label of a percentile, e.g. P99.9
+func FormatPercentile(p float64) string {
+	return "P" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// slot distances between the send and landing slots
+type SlotStats struct {
+	Min    float64
+	Max    float64
+	Avg    float64
+	Median float64
+	P90    float64
+	P95    float64
+	P99    float64
+}
+
+func ComputeSlotStats(distances []uint64) SlotStats {
+	var slots []float64
+	for _, v := range distances {
+		slots = append(slots, float64(v))
+	}
+
+	minDistance, _ := stats.Min(slots)
+	maxDistance, _ := stats.Max(slots)
+	avg, _ := stats.Mean(slots)
+	median, _ := stats.Median(slots)
+	p90, _ := stats.Percentile(slots, 90)
+	p95, _ := stats.Percentile(slots, 95)
+	p99, _ := stats.Percentile(slots, 99)
+
+	return SlotStats{
+		Min:    minDistance,
+		Max:    maxDistance,
+		Avg:    avg,
+		Median: median,
+		P90:    p90,
+		P95:    p95,
+		P99:    p99,
+	}
+}
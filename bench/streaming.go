@@ -0,0 +1,173 @@
+package bench
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+var (
+	// the csv file the records are written to as they land, only open with streaming_stats
+	TxRecordsFile *os.File
+
+	txRecordsMu     sync.Mutex
+	txRecordsWriter *csv.Writer
+)
+
+// StreamedRecords sums up the records of the landed transactions that were written to the csv file
+// and dropped from memory with streaming_stats, the stats computed from the records add them up
+type StreamedRecords struct {
+	Count     uint64
+	TotalSize int
+
+	// the landings with a known landing time
+	FirstLandTime time.Time
+	LastLandTime  time.Time
+
+	// the attempts the landings took, and the number of them that were resent
+	Attempts uint64
+	Resent   uint64
+
+	// the results of the groups of the summary, by group and key
+	Groups map[string]map[string]*GroupStats
+
+	// the landing times of the transactions of each block
+	BlockDeltas map[uint64]*Sketch
+}
+
+func NewStreamedRecords() *StreamedRecords {
+	return &StreamedRecords{
+		Groups:      make(map[string]map[string]*GroupStats),
+		BlockDeltas: make(map[uint64]*Sketch),
+	}
+}
+
+// OpenTxRecordsFile creates the csv file next to the log file, and writes its header
+func OpenTxRecordsFile() error {
+	file, err := os.OpenFile(TxRecordsFileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return fmt.Errorf("error opening transaction records file: %w", err)
+	}
+
+	TxRecordsFile = file
+	txRecordsWriter = csv.NewWriter(file)
+	txRecordsWriter.Write(TxRecordsHeader)
+
+	return nil
+}
+
+// CloseTxRecordsFile writes the rows left in the buffer and closes the csv file
+func CloseTxRecordsFile() error {
+	txRecordsMu.Lock()
+	defer txRecordsMu.Unlock()
+
+	txRecordsWriter.Flush()
+	err := txRecordsWriter.Error()
+
+	if closeErr := TxRecordsFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	TxRecordsFile = nil
+	txRecordsWriter = nil
+
+	return err
+}
+
+// writeTxRecordRow appends the row of the record to the csv file
+func writeTxRecordRow(label string, record *TxRecord) {
+	txRecordsMu.Lock()
+	defer txRecordsMu.Unlock()
+
+	txRecordsWriter.Write(TxRecordRow(label, record))
+}
+
+// isFinal reports whether nothing is left to record about the transaction, once it landed
+// and, when tracking all the commitment levels, was finalized
+func isFinal(record *TxRecord) bool {
+	if !record.Landed {
+		return false
+	}
+
+	if GlobalConfig.TrackAllCommitments {
+		_, ok := record.CommitmentDeltas[rpc.CommitmentFinalized]
+		return ok
+	}
+
+	return true
+}
+
+// streamRecord writes the record to the csv file and drops it once it's final, with streaming_stats,
+// the records are kept until the early landing rate was checked, and in listen only mode where they
+// spot the duplicate notifications, it must be called with the lock held
+func (b *Benchmark) streamRecord(record *TxRecord) {
+	if b.Streamed == nil || b.holdRecords || b.streamStopped || GlobalConfig.SplitMode == SplitModeListenOnly || !isFinal(record) {
+		return
+	}
+
+	writeTxRecordRow(b.Label(), record)
+	delete(b.TxRecords, record.Signature)
+
+	s := b.Streamed
+	s.Count += 1
+	s.TotalSize += record.Size
+	s.Attempts += record.Attempt
+	if record.Attempt > 1 {
+		s.Resent += 1
+	}
+
+	if record.HasLandingTime() {
+		if s.FirstLandTime.IsZero() || record.LandTime.Before(s.FirstLandTime) {
+			s.FirstLandTime = record.LandTime
+		}
+		if record.LandTime.After(s.LastLandTime) {
+			s.LastLandTime = record.LandTime
+		}
+
+		if _, ok := s.BlockDeltas[record.Slot]; !ok {
+			s.BlockDeltas[record.Slot] = NewSketch()
+		}
+		s.BlockDeltas[record.Slot].Add(float64(record.Delta))
+	}
+
+	for group, keyOf := range recordGroupKeys {
+		if _, ok := s.Groups[group]; !ok {
+			s.Groups[group] = make(map[string]*GroupStats)
+		}
+
+		key := keyOf(record)
+		groupStats, ok := s.Groups[group][key]
+		if !ok {
+			groupStats = &GroupStats{Key: key, Sketch: NewSketch()}
+			s.Groups[group][key] = groupStats
+		}
+
+		groupStats.Sent += 1
+		groupStats.Landed += 1
+		if record.HasLandingTime() {
+			groupStats.addDelta(record.Delta)
+		}
+	}
+}
+
+// stopStreaming keeps the records left at the end of the run, they're written along with the results
+func (b *Benchmark) stopStreaming() {
+	b.mu.Lock()
+	b.streamStopped = true
+	b.mu.Unlock()
+}
+
+// releaseRecords streams the final records held until the early landing rate was checked
+func (b *Benchmark) releaseRecords() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.holdRecords = false
+	for _, record := range b.TxRecords {
+		b.streamRecord(record)
+	}
+}
@@ -0,0 +1,162 @@
+package bench
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// SummaryRow is a line of the results summary, a row without a label ends a section
+type SummaryRow struct {
+	Label string
+	Value string
+}
+
+// Summary holds the lines of the results summary of a run
+type Summary struct {
+	Rows []SummaryRow
+}
+
+// Add appends a line to the summary
+func (s *Summary) Add(label string, format string, args ...any) {
+	s.Rows = append(s.Rows, SummaryRow{Label: label, Value: fmt.Sprintf(format, args...)})
+}
+
+// Section ends the current section of the summary
+func (s *Summary) Section() {
+	s.Rows = append(s.Rows, SummaryRow{})
+}
+
+// Display logs the summary in the format set with -format
+func (s *Summary) Display() {
+	if SummaryFormat != SummaryFormatTable {
+		for _, row := range s.Rows {
+			if row.Label == "" {
+				SimpleLogger.Printf("")
+				continue
+			}
+
+			SimpleLogger.Printf("%-22s : %s", row.Label, row.Value)
+		}
+		return
+	}
+
+	rows := [][]string{}
+	for _, row := range s.Rows {
+		if row.Label == "" {
+			// a nil row is drawn as a separator
+			rows = append(rows, nil)
+			continue
+		}
+
+		rows = append(rows, []string{row.Label, row.Value})
+	}
+
+	DisplayTable([]string{"Field", "Value"}, rows)
+}
+
+// DisplaySummaries logs the summaries of the runs side by side, one column per run,
+// the lines missing from a run are shown as "-"
+func DisplaySummaries(benchmarks []*Benchmark) {
+	type summaryLine struct {
+		label  string
+		values []string
+	}
+
+	lines := []*summaryLine{}
+	byKey := make(map[string]*summaryLine)
+	headers := []string{"Field"}
+
+	for i, b := range benchmarks {
+		headers = append(headers, b.Label())
+
+		// a label can repeat, e.g. with several send urls, so the lines are keyed by their occurrence
+		occurrences := make(map[string]int)
+		position := -1
+		for _, row := range b.Summary().Rows {
+			if row.Label == "" {
+				continue
+			}
+
+			key := fmt.Sprintf("%s#%d", row.Label, occurrences[row.Label])
+			occurrences[row.Label] += 1
+
+			line, ok := byKey[key]
+			if !ok {
+				line = &summaryLine{label: row.Label, values: make([]string, len(benchmarks))}
+				byKey[key] = line
+
+				// keep the lines of this run in order, after the previous one
+				lines = slices.Insert(lines, position+1, line)
+			}
+			line.values[i] = row.Value
+			position = slices.Index(lines, line)
+		}
+	}
+
+	rows := [][]string{}
+	for _, line := range lines {
+		row := []string{line.label}
+		for _, value := range line.values {
+			if value == "" {
+				value = "-"
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+
+	DisplayTable(headers, rows)
+}
+
+// DisplayTable logs the rows in an aligned table with borders, a nil row is drawn as a separator
+func DisplayTable(headers []string, rows [][]string) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	parts := []string{}
+	for _, width := range widths {
+		parts = append(parts, strings.Repeat("-", width+2))
+	}
+	border := "+" + strings.Join(parts, "+") + "+"
+
+	formatRow := func(cells []string) string {
+		padded := []string{}
+		for i, cell := range cells {
+			padded = append(padded, fmt.Sprintf(" %-*s ", widths[i], cell))
+		}
+		return "|" + strings.Join(padded, "|") + "|"
+	}
+
+	SimpleLogger.Printf("")
+	SimpleLogger.Printf("%s", border)
+	SimpleLogger.Printf("%s", formatRow(headers))
+	SimpleLogger.Printf("%s", border)
+
+	// skip the leading, trailing and repeated separators
+	separator := true
+	for _, row := range rows {
+		if row == nil {
+			if !separator {
+				SimpleLogger.Printf("%s", border)
+			}
+			separator = true
+			continue
+		}
+
+		SimpleLogger.Printf("%s", formatRow(row))
+		separator = false
+	}
+	if !separator {
+		SimpleLogger.Printf("%s", border)
+	}
+	SimpleLogger.Printf("")
+}
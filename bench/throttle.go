@@ -0,0 +1,60 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+)
+
+// ThrottleStats summarizes the time the transactions waited for the rate limiter before being sent,
+// only recorded in tx count mode, where each transaction waits for its turn
+type ThrottleStats struct {
+	Count     uint64
+	Throttled uint64
+	Total     time.Duration
+	Min       time.Duration
+	Max       time.Duration
+}
+
+// Avg returns the average wait of the transactions
+func (s ThrottleStats) Avg() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+
+	return s.Total / time.Duration(s.Count)
+}
+
+// recordThrottle records the time a transaction waited for the rate limiter
+func (b *Benchmark) recordThrottle(delay time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Throttle.Count == 0 || delay < b.Throttle.Min {
+		b.Throttle.Min = delay
+	}
+	b.Throttle.Max = max(b.Throttle.Max, delay)
+	b.Throttle.Total += delay
+	b.Throttle.Count += 1
+
+	if delay >= time.Millisecond {
+		b.Throttle.Throttled += 1
+	}
+}
+
+// ThrottleSummary describes the throttle delays, with a hint when the rate limiter held the transactions
+// back long enough to be the bottleneck rather than the RPC
+func (b *Benchmark) ThrottleSummary() string {
+	summary := fmt.Sprintf("min %s, avg %s, max %s (%d/%d txs throttled)",
+		b.Throttle.Min.Truncate(time.Millisecond),
+		b.Throttle.Avg().Truncate(time.Millisecond),
+		b.Throttle.Max.Truncate(time.Millisecond),
+		b.Throttle.Throttled,
+		b.Throttle.Count,
+	)
+
+	if b.Throttle.Avg() >= ThrottleHintDelay {
+		summary += ", rate_limit may be too low for tx_count"
+	}
+
+	return summary
+}
@@ -0,0 +1,147 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// the path the traces are posted to when otel_endpoint has none
+	DefaultOtelPath = "/v1/traces"
+
+	// time to flush the spans left in the exporter at the end of the test
+	OtelShutdownTimeout = 5 * time.Second
+)
+
+var (
+	// only set with otel_endpoint
+	TracerProvider *sdktrace.TracerProvider
+	Tracer         trace.Tracer
+)
+
+// StartTracing exports the spans of the runs and of their transactions to the OTLP/HTTP collector
+func StartTracing(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid otel_endpoint: %v", err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = DefaultOtelPath
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(u.String()))
+	if err != nil {
+		return fmt.Errorf("error creating the otel exporter: %v", err)
+	}
+
+	TracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "memobench"))),
+	)
+	Tracer = TracerProvider.Tracer("github.com/benjiewheeler/memobench")
+
+	log.Info("Exporting traces", "otel_endpoint", RedactUrl(u.String()))
+
+	return nil
+}
+
+// StopTracing flushes the spans left in the exporter
+func StopTracing() {
+	if TracerProvider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), OtelShutdownTimeout)
+	defer cancel()
+
+	if err := TracerProvider.Shutdown(ctx); err != nil {
+		log.Errorf("error flushing the traces: %v", err)
+	}
+}
+
+// startRunSpan starts the span of the run, the parent of the spans of its transactions
+func (b *Benchmark) startRunSpan() {
+	if Tracer == nil {
+		return
+	}
+
+	b.traceCtx, b.runSpan = Tracer.Start(b.ctx, "memobench.run", trace.WithAttributes(
+		attribute.String("test_id", b.TestID),
+		attribute.String("endpoint", b.Endpoint.GetLabel()),
+		attribute.Int64("run", int64(b.RunNumber)),
+	))
+}
+
+// endRunSpan ends the spans of the transactions that never landed, then the span of the run
+func (b *Benchmark) endRunSpan() {
+	if b.runSpan == nil {
+		return
+	}
+
+	b.mu.Lock()
+	for _, record := range b.TxRecords {
+		if record.span == nil || record.Landed {
+			continue
+		}
+
+		record.span.SetAttributes(attribute.Bool("landed", false))
+		record.span.SetStatus(codes.Error, "not landed")
+		record.span.End()
+	}
+	b.mu.Unlock()
+
+	b.runSpan.SetAttributes(
+		attribute.Int64("sent", int64(b.SentTransactions)),
+		attribute.Int64("landed", int64(b.ProcessedTransactions)),
+	)
+	b.runSpan.End()
+}
+
+// startTxSpan starts the span of the transaction at the start of its send, it must be called with the lock held
+func (b *Benchmark) startTxSpan(record *TxRecord, sendStart time.Time) {
+	if b.runSpan == nil {
+		return
+	}
+
+	_, record.span = Tracer.Start(b.traceCtx, "memobench.transaction", trace.WithTimestamp(sendStart), trace.WithAttributes(
+		attribute.String("signature", record.Signature.String()),
+		attribute.Int64("num", int64(record.Num)),
+		attribute.Int64("attempt", int64(record.Attempt)),
+		attribute.String("endpoint", b.Endpoint.GetLabel()),
+		attribute.String("test_id", b.TestID),
+		attribute.String("send_url", RedactUrl(record.SendUrl)),
+		attribute.Float64("send_latency_ms", durationToMs(record.SendLatency)),
+	))
+}
+
+// endTxSpan ends the span of the transaction at its landing, the span of a backfilled landing
+// ends when it was found since its landing time is unknown, it must be called with the lock held
+func (b *Benchmark) endTxSpan(record *TxRecord) {
+	if record.span == nil {
+		return
+	}
+
+	record.span.SetAttributes(
+		attribute.Bool("landed", true),
+		attribute.Int64("slot", int64(record.Slot)),
+	)
+
+	if record.Backfilled {
+		record.span.SetAttributes(attribute.Bool("backfilled", true))
+		record.span.End()
+		return
+	}
+
+	record.span.SetAttributes(attribute.Float64("delta_ms", durationToMs(record.Delta)))
+	record.span.End(trace.WithTimestamp(record.LandTime))
+}
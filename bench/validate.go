@@ -0,0 +1,383 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Validate checks the config values before the test starts,
+// the returned error names the offending field and how to fix it
+func (c *Config) Validate() error {
+	if c.PrivateKey == "" {
+		return fmt.Errorf("private_key must be set, either in the config file or in the %s environment variable", PrivateKeyEnvVar)
+	}
+
+	for i, endpoint := range c.GetEndpoints() {
+		// the fields are prefixed with the endpoint when several are compared
+		prefix := ""
+		if len(c.Endpoints) > 0 {
+			prefix = fmt.Sprintf("endpoints[%d].", i)
+		}
+
+		if endpoint.RpcUrl == "" {
+			return fmt.Errorf("%srpc_url must be set", prefix)
+		}
+		if err := validateUrl(prefix+"rpc_url", endpoint.RpcUrl, "http", "https"); err != nil {
+			return err
+		}
+		if err := validateUrl(prefix+"ws_url", endpoint.GetWsUrl(), "ws", "wss"); err != nil {
+			return err
+		}
+		if err := validateUrl(prefix+"send_rpc_url", endpoint.GetSendUrl(), "http", "https"); err != nil {
+			return err
+		}
+		for j, sendUrl := range endpoint.SendRpcUrls {
+			if err := validateUrl(fmt.Sprintf("%ssend_rpc_urls[%d]", prefix, j), sendUrl, "http", "https"); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, value := range c.RpcHeaders {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("rpc_headers contains an invalid header name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("rpc_headers value of %q must not contain line breaks", name)
+		}
+	}
+
+	// the ramp takes precedence over the rate limit
+	if c.IsRamp() {
+		if c.RateStart == 0 || c.RateEnd == 0 {
+			return errors.New("rate_start and rate_end must both be greater than 0")
+		}
+		if c.Duration == 0 {
+			return errors.New("rate_start and rate_end require duration to be set")
+		}
+		if c.RateSteps == 1 {
+			return errors.New("rate_steps must be at least 2")
+		}
+	} else if c.RateLimit == 0 {
+		return errors.New("rate_limit must be greater than 0")
+	}
+
+	// the sweep replaces the static and dynamic prices, the listen only side doesn't know the price of the transactions
+	if c.FeeMin > 0 || c.FeeMax > 0 || c.FeeSteps > 0 {
+		if c.FeeMax <= c.FeeMin {
+			return fmt.Errorf("fee_max must be greater than fee_min, got %d and %d", c.FeeMax, c.FeeMin)
+		}
+		if c.FeeSteps == 1 {
+			return errors.New("fee_steps must be at least 2")
+		}
+		if c.ComputeUnitPrice != 0 || c.PrioFee != 0 || c.GetPrioFeeMode() != PrioFeeModeStatic {
+			return errors.New("fee_min and fee_max must not be set along with compute_unit_price_microlamports, prio_fee nor the dynamic prio_fee_mode")
+		}
+		if c.SplitMode != "" {
+			return errors.New("fee_min and fee_max are not supported with split_mode")
+		}
+	}
+
+	// the transaction count is ignored in duration mode
+	if c.Duration == 0 && c.TxCount == 0 {
+		return errors.New("tx_count must be greater than 0, or duration must be set")
+	}
+
+	if c.PrioFee < 0 {
+		return fmt.Errorf("prio_fee must not be negative, got %v", c.PrioFee)
+	}
+	if c.PrioFee != 0 && c.ComputeUnitPrice != 0 {
+		return errors.New("prio_fee and compute_unit_price_microlamports must not both be set, prio_fee is deprecated")
+	}
+
+	// verify the commitment level is supported
+	switch c.GetCommitment() {
+	case rpc.CommitmentProcessed, rpc.CommitmentConfirmed, rpc.CommitmentFinalized:
+	default:
+		return fmt.Errorf("commitment must be one of processed, confirmed or finalized, got %q", c.Commitment)
+	}
+
+	switch rpc.CommitmentType(c.BlockhashCommitment) {
+	case "", rpc.CommitmentProcessed, rpc.CommitmentConfirmed, rpc.CommitmentFinalized:
+	default:
+		return fmt.Errorf("blockhash_commitment must be one of processed, confirmed or finalized, got %q", c.BlockhashCommitment)
+	}
+
+	// the RPC uses its default commitment if not set
+	switch rpc.CommitmentType(c.PreflightCommitment) {
+	case "", rpc.CommitmentProcessed, rpc.CommitmentConfirmed, rpc.CommitmentFinalized:
+		if c.PreflightCommitment != "" && c.GetSkipPreflight() {
+			return errors.New("preflight_commitment requires skip_preflight to be false")
+		}
+	default:
+		return fmt.Errorf("preflight_commitment must be one of processed, confirmed or finalized, got %q", c.PreflightCommitment)
+	}
+
+	for _, p := range c.Percentiles {
+		if p <= 0 || p >= 100 {
+			return fmt.Errorf("percentiles must be in the range (0, 100), got %v", p)
+		}
+	}
+
+	// verify the priority fee mode is supported
+	switch c.GetPrioFeeMode() {
+	case PrioFeeModeStatic:
+	case PrioFeeModeDynamic:
+		if p := c.GetPrioFeePercentile(); p <= 0 || p > 100 {
+			return fmt.Errorf("prio_fee_percentile must be in the range (0, 100], got %v", p)
+		}
+	default:
+		return fmt.Errorf("prio_fee_mode must be either static or dynamic, got %q", c.PrioFeeMode)
+	}
+
+	// verify the send mode is supported
+	switch c.GetSendMode() {
+	case SendModeRpc:
+	case SendModeJito:
+		if c.JitoUrl == "" {
+			return errors.New("jito_url must be set in jito send mode")
+		}
+		if err := validateUrl("jito_url", c.JitoUrl, "http", "https"); err != nil {
+			return err
+		}
+		if c.JitoTip < MinJitoTip {
+			return fmt.Errorf("jito_tip must be at least %d Lamports, got %d", MinJitoTip, c.JitoTip)
+		}
+	default:
+		return fmt.Errorf("send_mode must be either rpc or jito, got %q", c.SendMode)
+	}
+
+	// verify the confirm mode is supported
+	switch c.GetConfirmMode() {
+	case ConfirmModeWs, ConfirmModePoll, ConfirmModeBoth:
+	default:
+		return fmt.Errorf("confirm_mode must be one of ws, poll or both, got %q", c.ConfirmMode)
+	}
+
+	// verify the confirm source is supported
+	switch c.GetConfirmSource() {
+	case ConfirmSourceWs:
+	case ConfirmSourceGeyser:
+		if c.GetConfirmMode() == ConfirmModePoll {
+			return errors.New("confirm_source geyser requires confirm_mode to be ws or both")
+		}
+		if c.GeyserUrl == "" {
+			return errors.New("geyser_url must be set when confirm_source is geyser")
+		}
+		if err := validateUrl("geyser_url", c.GeyserUrl, "http", "https"); err != nil {
+			return err
+		}
+	case ConfirmSourceBlock:
+		if c.GetConfirmMode() == ConfirmModePoll {
+			return errors.New("confirm_source block requires confirm_mode to be ws or both")
+		}
+		// the block notifications are only sent once the blocks are confirmed
+		if c.GetCommitment() == rpc.CommitmentProcessed {
+			return errors.New("confirm_source block requires commitment to be confirmed or finalized")
+		}
+	case ConfirmSourceSignature:
+		if c.GetConfirmMode() == ConfirmModePoll {
+			return errors.New("confirm_source signature requires confirm_mode to be ws or both")
+		}
+	default:
+		return fmt.Errorf("confirm_source must be one of ws, geyser, block or signature, got %q", c.ConfirmSource)
+	}
+
+	// verify the transaction version is supported
+	switch c.GetTxVersion() {
+	case TxVersionLegacy:
+		if c.LookupTable != "" {
+			return errors.New("lookup_table requires tx_version to be 0")
+		}
+	case TxVersionV0:
+		if c.LookupTable != "" {
+			if _, err := solana.PublicKeyFromBase58(c.LookupTable); err != nil {
+				return fmt.Errorf("error parsing lookup_table: %w", err)
+			}
+		}
+	default:
+		return fmt.Errorf("tx_version must be either 0 or legacy, got %q", c.TxVersion)
+	}
+
+	if c.SendTimeout < 0 {
+		return fmt.Errorf("send_timeout must not be negative, got %v", c.SendTimeout)
+	}
+
+	if c.StartDelay != nil && *c.StartDelay < 0 {
+		return fmt.Errorf("start_delay must not be negative, got %v", *c.StartDelay)
+	}
+
+	// the duration mode already paces the sends
+	if c.SendWindow < 0 {
+		return fmt.Errorf("send_window must not be negative, got %v", c.SendWindow)
+	}
+	if c.SendWindow > 0 && c.Duration > 0 {
+		return errors.New("send_window must not be set along with duration")
+	}
+
+	// the expiry of a transaction is only known if its landing is tracked by the same instance
+	if c.MaxResends > 0 && (c.SplitMode != "" || c.DryRun) {
+		return errors.New("max_resends is not supported with split_mode nor dry_run")
+	}
+
+	// the landings of the other side are unknown in split mode
+	if c.CrossCheckWsUrl != "" {
+		if err := validateUrl("cross_check_ws_url", c.CrossCheckWsUrl, "ws", "wss"); err != nil {
+			return err
+		}
+		if c.SplitMode != "" {
+			return errors.New("cross_check_ws_url is not supported with split_mode")
+		}
+		if c.StreamingStats {
+			return errors.New("cross_check_ws_url is not supported with streaming_stats, the signatures of the landed transactions aren't kept")
+		}
+	}
+
+	// the control transactions are sent and confirmed by the same instance, without a tip
+	if c.ControlRate > 0 {
+		if c.ControlCuPrice == 0 {
+			return errors.New("control_rate requires control_compute_unit_price_microlamports")
+		}
+		if c.SplitMode != "" || c.DryRun || c.GetSendMode() == SendModeJito {
+			return errors.New("control_rate is not supported with split_mode, dry_run nor the jito send_mode")
+		}
+	}
+
+	if c.OtelEndpoint != "" {
+		if err := validateUrl("otel_endpoint", c.OtelEndpoint, "http", "https"); err != nil {
+			return err
+		}
+	}
+
+	if c.WebhookUrl != "" {
+		if err := validateUrl("webhook_url", c.WebhookUrl, "http", "https"); err != nil {
+			return err
+		}
+	}
+
+	// a file url has no host, the bucket is the host of an s3 url
+	if c.ResultsOutput != "" {
+		if strings.HasPrefix(c.ResultsOutput, ResultsSchemeFile+"://") {
+			if u, err := url.Parse(c.ResultsOutput); err != nil || u.Path == "" {
+				return fmt.Errorf("results_output must be a file:// url with an absolute path, got %q", c.ResultsOutput)
+			}
+		} else if err := validateUrl("results_output", c.ResultsOutput, ResultsSchemeFile, ResultsSchemeHttp, ResultsSchemeHttps, ResultsSchemeS3); err != nil {
+			return err
+		}
+	}
+
+	if c.MemoProgramId != "" {
+		if _, err := solana.PublicKeyFromBase58(c.MemoProgramId); err != nil {
+			return fmt.Errorf("error parsing memo_program_id: %w", err)
+		}
+	}
+
+	// verify the workload is supported
+	switch c.GetWorkload() {
+	case WorkloadMemo, WorkloadTransfer:
+	case WorkloadProgram:
+		if c.WorkloadProgramId == "" {
+			return errors.New("workload_program_id must be set in program workload")
+		}
+		if _, err := solana.PublicKeyFromBase58(c.WorkloadProgramId); err != nil {
+			return fmt.Errorf("error parsing workload_program_id: %w", err)
+		}
+		if size := c.GetWorkloadDataSize(); size < MinWorkloadDataSize || size > MaxWorkloadDataSize {
+			return fmt.Errorf("workload_data_size must be in the range [%d, %d], got %d", MinWorkloadDataSize, MaxWorkloadDataSize, size)
+		}
+	default:
+		return fmt.Errorf("workload must be one of memo, transfer or program, got %q", c.Workload)
+	}
+
+	if c.SendBatchSize > 1 && c.GetSendMode() != SendModeRpc {
+		return errors.New("send_batch_size requires send_mode to be rpc")
+	}
+
+	// a batch only fills up with as many concurrent sends
+	if c.Workers > 0 && c.SendBatchSize > c.Workers {
+		return fmt.Errorf("workers must be at least send_batch_size, got %d", c.Workers)
+	}
+
+	if c.TxSizeBytes > MaxTxSize {
+		return fmt.Errorf("tx_size_bytes must not exceed %d, got %d", MaxTxSize, c.TxSizeBytes)
+	}
+
+	if c.MinLandingRate < 0 || c.MinLandingRate > 1 {
+		return fmt.Errorf("min_landing_rate must be in the range [0, 1], got %v", c.MinLandingRate)
+	}
+
+	if c.EarlyAbortThreshold < 0 || c.EarlyAbortThreshold > 1 {
+		return fmt.Errorf("early_abort_threshold must be in the range [0, 1], got %v", c.EarlyAbortThreshold)
+	}
+
+	if (c.EarlyAbortWindow > 0) != (c.EarlyAbortThreshold > 0) {
+		return errors.New("early_abort_window and early_abort_threshold must be set together")
+	}
+
+	if c.EarlyAbortWindow > 0 && c.Duration == 0 && c.EarlyAbortWindow >= c.TxCount {
+		return fmt.Errorf("early_abort_window must be lower than tx_count, got %d", c.EarlyAbortWindow)
+	}
+
+	// verify the split mode is supported
+	switch c.SplitMode {
+	case "":
+	case SplitModeSendOnly, SplitModeListenOnly:
+		if len(c.GetEndpoints()) > 1 || c.GetRepeat() > 1 {
+			return fmt.Errorf("split_mode %s requires a single endpoint and run", c.SplitMode)
+		}
+		if c.MinLandingRate > 0 || c.EarlyAbortWindow > 0 || c.TrackAllCommitments {
+			return fmt.Errorf("split_mode %s doesn't support min_landing_rate, early_abort_window and track_all_commitments", c.SplitMode)
+		}
+	default:
+		return fmt.Errorf("split_mode must be either send_only or listen_only, got %q", c.SplitMode)
+	}
+
+	// the transactions are only known by their memo, which must match the test id of the sender
+	if c.SplitMode == SplitModeListenOnly {
+		if c.TestID == "" {
+			return errors.New("test_id must be set to the test id of the sender in listen_only split_mode")
+		}
+		if c.GetConfirmMode() != ConfirmModeWs || c.GetConfirmSource() != ConfirmSourceWs || c.GetWorkload() != WorkloadMemo {
+			return errors.New("listen_only split_mode requires confirm_mode ws, confirm_source ws and the memo workload")
+		}
+		if !c.LogsMemos() {
+			return errors.New("listen_only split_mode requires the default memo_program_id, the memos of the other programs aren't logged")
+		}
+	}
+
+	// verify the memo template can be matched by the listener
+	if err := ValidateMemoTemplate(c.GetMemoTemplate()); err != nil {
+		return err
+	}
+
+	// verify the compute unit limit is within the allowed range
+	if c.GetComputeUnitLimit() > MaxComputeUnitLimit {
+		return fmt.Errorf("compute_unit_limit must not exceed %d, got %d", MaxComputeUnitLimit, c.GetComputeUnitLimit())
+	}
+
+	return nil
+}
+
+// validateUrl checks the url parses, uses one of the schemes and has a host
+func validateUrl(field string, rawUrl string, schemes ...string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %w", field, err)
+	}
+
+	if !slices.Contains(schemes, u.Scheme) {
+		return fmt.Errorf("%s must use the %s scheme, got %q", field, strings.Join(schemes, " or "), RedactUrl(rawUrl))
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("%s must contain a host, got %q", field, RedactUrl(rawUrl))
+	}
+
+	return nil
+}
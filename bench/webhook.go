@@ -0,0 +1,48 @@
+package bench
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// PostResults sends the results to the webhook as JSON, the same payload as the results file,
+// the failed attempts are retried with a growing delay
+func PostResults(webhookUrl string, results *Results) error {
+	data, err := EncodeResults(results)
+	if err != nil {
+		return err
+	}
+
+	// a webhook that is down must not hold the end of the test
+	client := &http.Client{Timeout: WebhookTimeout}
+
+	delay := WebhookRetryDelay
+	for attempt := 1; ; attempt++ {
+		err = postJson(client, webhookUrl, data)
+		if err == nil || attempt >= MaxWebhookAttempts {
+			return err
+		}
+
+		log.Warn("Unable to post the results to the webhook, retrying", "attempt", fmt.Sprintf("%d/%d", attempt, MaxWebhookAttempts), "delay", delay, "err", err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func postJson(client *http.Client, url string, data []byte) error {
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package bench
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+const (
+	// workloads, the memo one is matched by its memo, the others by their signature
+	WorkloadMemo     = "memo"
+	WorkloadTransfer = "transfer"
+	WorkloadProgram  = "program"
+
+	// default and allowed size of the instruction data of the program workload,
+	// the data starts with the transaction number to keep the transactions unique
+	DefaultWorkloadDataSize = 32
+	MinWorkloadDataSize     = 8
+	MaxWorkloadDataSize     = 1024
+)
+
+// Workload builds the measured instruction of each test transaction
+type Workload interface {
+	Instruction(id uint64, testID string, wallet solana.PublicKey) solana.Instruction
+}
+
+// NewWorkload creates the workload selected in the config
+func NewWorkload(config *Config) Workload {
+	switch config.GetWorkload() {
+	case WorkloadTransfer:
+		return &TransferWorkload{}
+	case WorkloadProgram:
+		return &ProgramWorkload{
+			ProgramID: solana.MustPublicKeyFromBase58(config.WorkloadProgramId),
+			DataSize:  config.GetWorkloadDataSize(),
+		}
+	default:
+		return &MemoWorkload{ProgramID: config.GetMemoProgramId(), Template: config.GetMemoTemplate()}
+	}
+}
+
+// MemoWorkload writes a memo tagged with the transaction number and the test ID
+type MemoWorkload struct {
+	ProgramID solana.PublicKey
+	Template  string
+}
+
+func (w *MemoWorkload) Instruction(id uint64, testID string, wallet solana.PublicKey) solana.Instruction {
+	return MemoInstruction(w.ProgramID, FormatMemo(w.Template, id, testID), wallet)
+}
+
+func MemoInstruction(programID solana.PublicKey, memo string, wallet solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(
+		programID,
+		solana.AccountMetaSlice{
+			solana.NewAccountMeta(wallet, false, true),
+		},
+		[]byte(memo),
+	)
+}
+
+// PaddingInstruction builds a memo of the given length without any signer,
+// it only adds to the size of the transaction
+func PaddingInstruction(length int) solana.Instruction {
+	return solana.NewInstruction(GlobalConfig.GetMemoProgramId(), solana.AccountMetaSlice{}, []byte(strings.Repeat(".", length)))
+}
+
+// TransferWorkload transfers lamports from the wallet to itself,
+// the amount is the transaction number to keep the transactions unique
+type TransferWorkload struct{}
+
+func (w *TransferWorkload) Instruction(id uint64, testID string, wallet solana.PublicKey) solana.Instruction {
+	return system.NewTransferInstruction(id, wallet, wallet).Build()
+}
+
+// ProgramWorkload invokes the program with data of the given size and no account,
+// the program is expected to be a no-op, e.g. one that only succeeds
+type ProgramWorkload struct {
+	ProgramID solana.PublicKey
+	DataSize  uint64
+}
+
+func (w *ProgramWorkload) Instruction(id uint64, testID string, wallet solana.PublicKey) solana.Instruction {
+	// the transaction number followed by the test ID, truncated or padded with zeros
+	data := make([]byte, w.DataSize)
+	binary.LittleEndian.PutUint64(data, id)
+	copy(data[8:], testID)
+
+	return solana.NewInstruction(w.ProgramID, solana.AccountMetaSlice{}, data)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/benjiewheeler/memobench/bench"
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// the amount airdropped to the new test wallet on devnet
+	InitAirdropLamports = 1 * solana.LAMPORTS_PER_SOL
+
+	// time to wait for the genesis hash of the rpc url
+	InitRpcTimeout = 10 * time.Second
+)
+
+// RunInit walks through the creation of a config file: the rpc url, the test wallet,
+// and an airdrop to fund it on devnet, then saves the config and exits
+func RunInit(args []string) {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	flags.StringVar(&ConfigFileName, "config", ConfigFileName, "the path of the config file to create")
+	flags.Parse(args)
+
+	input := bufio.NewReader(os.Stdin)
+
+	if _, err := os.Stat(ConfigFileName); err == nil {
+		if !Confirm(input, fmt.Sprintf("%s already exists, overwrite it?", ConfigFileName), false) {
+			os.Exit(0)
+		}
+	}
+
+	config := bench.DEFAULT_CONFIG
+
+	for {
+		config.RpcUrl = Prompt(input, "RPC URL", "https://api.devnet.solana.com")
+		if strings.HasPrefix(config.RpcUrl, "http://") || strings.HasPrefix(config.RpcUrl, "https://") {
+			break
+		}
+		fmt.Println("The RPC URL must be an http(s):// url")
+	}
+
+	// an empty key generates a new wallet
+	var account solana.PrivateKey
+	for {
+		privateKey := Prompt(input, "Private key of the test wallet, in base58 (leave empty to generate one)", "")
+		if privateKey == "" {
+			generated, err := solana.NewRandomPrivateKey()
+			if err != nil {
+				log.Fatalf("error generating private key: %v", err)
+			}
+
+			account = generated
+			break
+		}
+
+		parsed, err := solana.PrivateKeyFromBase58(privateKey)
+		if err == nil {
+			account = parsed
+			break
+		}
+		fmt.Printf("Invalid private key: %v\n", err)
+	}
+	config.PrivateKey = account.String()
+
+	if err := WriteConfig(&config); err != nil {
+		log.Fatalf("error saving config file: %v", err)
+	}
+	log.Info("config file saved", "path", ConfigFileName, "address", account.PublicKey())
+
+	rpcClient := rpc.New(config.RpcUrl)
+
+	ctx, cancel := context.WithTimeout(context.Background(), InitRpcTimeout)
+	defer cancel()
+
+	// the cluster is identified by its genesis hash rather than the url, which may be a custom domain
+	genesisHash, err := rpcClient.GetGenesisHash(ctx)
+	if err != nil {
+		log.Warn("Unable to identify the cluster of the RPC URL, fund the test wallet and run memobench", "err", err)
+		return
+	}
+
+	if genesisHash.String() != bench.DevnetGenesisHash {
+		log.Info("Not a devnet RPC URL, no airdrop is requested, fund the test wallet and run memobench", "address", account.PublicKey())
+		return
+	}
+
+	if !Confirm(input, fmt.Sprintf("Airdrop %d SOL to the test wallet?", InitAirdropLamports/solana.LAMPORTS_PER_SOL), true) {
+		log.Info("Fund the test wallet and run memobench", "address", account.PublicKey())
+		return
+	}
+
+	if err := bench.Airdrop(context.Background(), rpcClient, account.PublicKey(), InitAirdropLamports); err != nil {
+		log.Warn("Airdrop failed, the devnet faucet may be rate limited, fund the test wallet from https://faucet.solana.com and run memobench", "address", account.PublicKey(), "err", err)
+		return
+	}
+
+	log.Info("Test wallet funded, run memobench to start the test", "address", account.PublicKey())
+}
+
+// Prompt asks a question on the console and returns the trimmed answer, or the default value if empty
+func Prompt(input *bufio.Reader, question string, defaultValue string) string {
+	if defaultValue != "" {
+		fmt.Printf("%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+
+	answer, err := input.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		log.Fatalf("error reading the answer: %v", err)
+	}
+
+	// the remaining questions can't be answered once the input is closed
+	if errors.Is(err, io.EOF) && answer == "" {
+		fmt.Println()
+		log.Fatal("no answer, the input was closed")
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return defaultValue
+	}
+
+	return answer
+}
+
+// Confirm asks a yes or no question on the console
+func Confirm(input *bufio.Reader, question string, defaultYes bool) bool {
+	choices := "y/N"
+	if defaultYes {
+		choices = "Y/n"
+	}
+
+	for {
+		switch strings.ToLower(Prompt(input, fmt.Sprintf("%s (%s)", question, choices), "")) {
+		case "":
+			return defaultYes
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+	}
+}
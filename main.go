@@ -2,258 +2,113 @@ package main
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
-	"math"
+	"net/http"
 	"os"
 	"os/signal"
-	"regexp"
+	"runtime"
 	"strings"
-	"sync"
 	"syscall"
 	"time"
 
+	"github.com/benjiewheeler/memobench/bench"
 	"github.com/charmbracelet/log"
 	"github.com/gagliardetto/solana-go"
-	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
-	"github.com/gagliardetto/solana-go/rpc"
-	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
-	"github.com/gagliardetto/solana-go/rpc/ws"
-	"github.com/montanaflynn/stats"
-	"golang.org/x/text/language"
-	"golang.org/x/text/message"
-	"golang.org/x/time/rate"
 )
 
 const (
-	ComputeUnitLimit = 30000
+	// the -config value to read the config from stdin
+	ConfigStdin = "-"
+
+	// time to wait for the config to be fetched when it's read from a url
+	ConfigFetchTimeout = 10 * time.Second
 )
 
 var Version string = "development"
 
-var (
-	DEFAULT_CONFIG = Config{
-		RpcUrl:    "http://node.foo.cc",
-		RateLimit: 200,
-		TxCount:   100,
-		PrioFee:   0,
-	}
-
-	TestID string
-
-	// variable for the log file; set to benchmark.log as a fallback
-	LogFileName string = "benchmark.log"
-
-	GlobalConfig *Config
-	TestAccount  *solana.PrivateKey
-
-	wg sync.WaitGroup
-	mu sync.RWMutex
-
-	// the rate limiter
-	Limiter = rate.NewLimiter(rate.Limit(200), 200)
+// the build time, set with -ldflags "-X main.BuildTime=..." like the version
+var BuildTime string
 
-	// the time the test should end
-	StopTime time.Time
-
-	// the number of transactions sent and transactions that landed
-	SentTransactions      uint64
-	ProcessedTransactions uint64
-
-	// transaction send times
-	TxTimes = make(map[solana.Signature]time.Time)
-
-	// delta between transaction send times and landing times
-	TxDeltas = []time.Duration{}
-
-	// blocks where transactions landed
-	TxBlocks = make(map[uint64]uint64)
-
-	WsListener *WebsocketListener
-
-	SimpleLogger *log.Logger
+var (
+	// the path of the config file, set with the -config flag
+	ConfigFileName string = "config.json"
+
+	// the values passed on the command line to override the config file
+	FlagRpcUrl    string
+	FlagRateLimit uint64
+	FlagTxCount   uint64
+	FlagPrioFee   float64
+	FlagCuPrice   uint64
+	FlagQuiet     bool
+	FlagDryRun    bool
+	FlagLogDir    string
+	FlagTestID    string
+
+	// the path of the results file to compare the test with
+	FlagCompare string
+
+	// print the resolved config instead of running the test
+	FlagPrintConfig bool
+
+	// hide the banner, e.g. when the output is parsed
+	FlagNoBanner bool
+
+	// the format of the results summary
+	FlagFormat string
+
+	// print the version and exit
+	FlagVersion bool
 )
 
-type Config struct {
-	PrivateKey  string  `json:"private_key"`
-	RpcUrl      string  `json:"rpc_url"`
-	WsUrl       string  `json:"ws_url"`
-	SendRpcUrl  string  `json:"send_rpc_url"`
-	RateLimit   uint64  `json:"rate_limit"`
-	TxCount     uint64  `json:"tx_count"`
-	PrioFee     float64 `json:"prio_fee"`
-	NodeRetries uint    `json:"node_retries"`
+// IsLocalConfig reports whether the config is read from a local file, rather than stdin or a url
+func IsLocalConfig(path string) bool {
+	return path != ConfigStdin && !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://")
 }
 
-func (c *Config) GetWsUrl() string {
-	if c.WsUrl != "" {
-		return c.WsUrl
+// LoadConfigData reads the raw config from the local file, stdin if the path is "-", or the http(s) url
+func LoadConfigData(path string) ([]byte, error) {
+	if path == ConfigStdin {
+		return io.ReadAll(os.Stdin)
 	}
 
-	// replace http:// with ws:// and https:// with wss://
-	return strings.ReplaceAll(strings.ReplaceAll(c.RpcUrl, "http://", "ws://"), "https://", "wss://")
-}
-
-func (c *Config) GetSendUrl() string {
-	if c.SendRpcUrl != "" {
-		return c.SendRpcUrl
+	if IsLocalConfig(path) {
+		return os.ReadFile(path)
 	}
 
-	return c.RpcUrl
-}
-
-type WebsocketListener struct {
-	Subscription *ws.LogSubscription
-	Listening    bool
-}
-
-func (l *WebsocketListener) Start() {
-	wsClient, err := ws.Connect(context.TODO(), GlobalConfig.GetWsUrl())
+	client := &http.Client{Timeout: ConfigFetchTimeout}
+	resp, err := client.Get(path)
 	if err != nil {
-		log.Fatalf("error connecting to websocket: %v", err)
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	defer wg.Done()
-
-	// invoke the default stop timer
-	time.AfterFunc(time.Until(StopTime), WsListener.Stop)
-
-	l.Subscription, err = wsClient.LogsSubscribeMentions(TestAccount.PublicKey(), rpc.CommitmentProcessed)
-	if err != nil {
-		log.Fatalf("error subscribing to logs: %v", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
 	}
-	l.Listening = true
 
-	log.Info("Listening for transactions...")
-
-	// start sending transactions now that the websocket is ready
-	SendTransactions()
-
-	for l.Listening {
-		got, err := l.Subscription.Recv()
-		if err != nil {
-			log.Error(err.Error())
-		}
-
-		if got == nil || got.Value.Err != nil {
-			continue
-		}
-
-		re := regexp.MustCompile(`memobench:.*?(\d+).*\[(.*?)\]`)
-		for _, line := range got.Value.Logs {
-			matches := re.FindStringSubmatch(line)
-			if len(matches) != 3 {
-				continue
-			}
-			testNum, id := matches[1], matches[2]
-
-			if id != TestID {
-				log.Warn(
-					"Received unexpected test ID",
-					"num", testNum,
-					"id", id,
-					"sig", got.Value.Signature.String(),
-				)
-				continue
-			}
-
-			var delta time.Duration
-			mu.Lock()
-			// record the time delta
-			txSendTime, found := TxTimes[got.Value.Signature]
-			if found {
-				ProcessedTransactions += 1
-				delta = time.Since(txSendTime)
-				TxDeltas = append(TxDeltas, delta)
-
-				// record the block where the tx landed
-				// add new entry if needed
-				if _, ok := TxBlocks[got.Context.Slot]; !ok {
-					TxBlocks[got.Context.Slot] = 0
-				}
-
-				// increment the tx count for this block
-				TxBlocks[got.Context.Slot] += 1
-			}
-
-			mu.Unlock()
-
-			// skip this tx if it's not in the TxTimes map
-			// this could happen if the test was restarted and a tx from a previous test landed
-			if !found {
-				continue
-			}
-
-			log.Info(
-				"Tx Processed",
-				"num", testNum,
-				"sig", got.Value.Signature.String(),
-				"delta", delta.Truncate(time.Millisecond).String(),
-				"landed", fmt.Sprintf("%d/%d", ProcessedTransactions, SentTransactions),
-			)
-
-			if ProcessedTransactions >= SentTransactions {
-				l.Stop()
-			}
-			break
-		}
-	}
-
-	log.Info("Stopping listening for log events...")
-}
-
-func (l *WebsocketListener) Stop() {
-	if !l.Listening {
-		return
-	}
-
-	l.Listening = false
-	l.Subscription.Unsubscribe()
+	return io.ReadAll(resp.Body)
 }
 
-func SetupLogger() {
-	LogFileName = fmt.Sprintf("memobench_%d_%s.log", time.Now().UnixMilli(), TestID)
-	logFile, err := os.OpenFile(LogFileName, os.O_RDWR|os.O_CREATE, 0666)
+func ReadConfig() *bench.Config {
+	data, err := LoadConfigData(ConfigFileName)
 	if err != nil {
-		log.Fatalf("error opening file: %v", err)
-	}
-
-	multi := io.MultiWriter(os.Stdout, logFile)
-
-	// create a simplified logger for logging the test results
-	SimpleLogger = log.NewWithOptions(multi, log.Options{
-		ReportTimestamp: false,
-	})
-
-	// set the default logger for logging during the test
-	log.SetDefault(log.NewWithOptions(multi, log.Options{
-		Prefix:          TestID,
-		ReportTimestamp: true,
-		TimeFunction:    func(time.Time) time.Time { return time.Now().UTC() },
-		TimeFormat:      "15:04:05.0000",
-	}))
-}
-
-func ReadConfig() *Config {
-	data, err := os.ReadFile("config.json")
-	if err != nil {
-		// if the error is that the file doesn't exist, create it, and exit
-		if os.IsNotExist(err) {
-			if err := WriteConfig(&DEFAULT_CONFIG); err != nil {
+		// if the error is that the local file doesn't exist, create it, and exit
+		if os.IsNotExist(err) && IsLocalConfig(ConfigFileName) {
+			if err := WriteConfig(&bench.DEFAULT_CONFIG); err != nil {
 				log.Fatalf("error creating config file: %v", err)
 			}
 
-			log.Info("config file saved, edit the config and restart")
+			log.Info("config file saved, edit the config and restart, or run memobench init for a guided setup", "path", ConfigFileName)
 			os.Exit(0)
 		}
 
-		log.Fatalf("error opening config file: %v", err)
+		log.Fatalf("error reading config from %s: %v", ConfigLabel(), err)
 	}
 
-	var out Config
+	var out bench.Config
 
 	err = json.Unmarshal(data, &out)
 	if err != nil {
@@ -263,203 +118,181 @@ func ReadConfig() *Config {
 	return &out
 }
 
-func WriteConfig(config *Config) error {
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		log.Fatalf("error saving config file: %v", err)
+// ConfigLabel returns where the config is read from, for the logs
+func ConfigLabel() string {
+	if ConfigFileName == ConfigStdin {
+		return "stdin"
 	}
 
-	return os.WriteFile("config.json", data, 0644)
+	return bench.RedactUrl(ConfigFileName)
 }
 
-func VerifyPrivateKey(base58key string) {
-	account, err := solana.PrivateKeyFromBase58(base58key)
+func WriteConfig(config *bench.Config) error {
+	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
-		log.Fatalf("error parsing private key: %v", err)
+		log.Fatalf("error saving config file: %v", err)
 	}
-	TestAccount = &account
-}
 
-func AssertSufficientBalance() {
-	// Create a new RPC client:
-	rpcClient := rpc.New(GlobalConfig.RpcUrl)
+	return os.WriteFile(ConfigFileName, data, 0644)
+}
 
-	// fetch the latest blockhash
-	balance, err := rpcClient.GetBalance(context.TODO(), TestAccount.PublicKey(), rpc.CommitmentFinalized)
-	if err != nil || balance == nil {
-		log.Fatalf("error getting test wallet balance: %v", err)
+// ShowBanner reports whether the banner is printed, only on an interactive terminal,
+// since it would end up in the redirected output or the printed config
+func ShowBanner() bool {
+	if FlagQuiet || FlagPrintConfig || FlagNoBanner {
+		return false
 	}
 
-	costPerTx := uint64(GlobalConfig.PrioFee*ComputeUnitLimit + 5000)
-	totalCost := GlobalConfig.TxCount * costPerTx
-
-	// abort if balance is less than 50% of the maximum cost
-	if balance.Value < totalCost/2 {
-		log.Fatal(
-			"Insufficient balance in test wallet.",
-			"balance", fmt.Sprintf("%.6f SOL", float64(balance.Value)/float64(solana.LAMPORTS_PER_SOL)),
-			"required", fmt.Sprintf("%.6f SOL", float64(totalCost)/float64(solana.LAMPORTS_PER_SOL)),
-		)
-	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
-func SendTransactions() {
-	// Create a new RPC client:
-	rpcClient := rpc.New(GlobalConfig.RpcUrl)
+// PrintConfig prints the resolved config as JSON, with the secrets redacted, and exits
+func PrintConfig(config *bench.Config) {
+	redacted := config.Redacted()
 
-	// create the send client
-	sendClient := rpc.New(GlobalConfig.GetSendUrl())
-
-	// fetch the latest blockhash
-	recent, err := rpcClient.GetLatestBlockhash(context.TODO(), rpc.CommitmentFinalized)
+	data, err := json.MarshalIndent(&redacted, "", "  ")
 	if err != nil {
-		log.Fatalf("error getting recent blockhash: %v", err)
+		log.Fatalf("error encoding config: %v", err)
 	}
 
-	// save current time and set the experiment end time
-	// hash expire after 150 blocks, each block is about 400ms
-	// we use 160 blocks just out of abundance of caution
-	StopTime = time.Now().Add(160 * 400 * time.Millisecond)
-	time.AfterFunc(time.Until(StopTime), WsListener.Stop)
+	fmt.Println(string(data))
+	os.Exit(0)
+}
 
-	for i := uint64(0); i < GlobalConfig.TxCount; i++ {
-		go func(id uint64) {
-			instructions := []solana.Instruction{}
+// PrintVersion prints the version, the Go version and the build time if known, and exits
+func PrintVersion() {
+	info := fmt.Sprintf("%s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if BuildTime != "" {
+		info += ", built " + BuildTime
+	}
 
-			if GlobalConfig.PrioFee > 0 {
-				instructions = append(instructions, computebudget.NewSetComputeUnitPriceInstruction(uint64(GlobalConfig.PrioFee*1e6)).Build())
-				instructions = append(instructions, computebudget.NewSetComputeUnitLimitInstruction(ComputeUnitLimit).Build())
-			}
+	fmt.Printf("memobench %s (%s)\n", Version, info)
+	os.Exit(0)
+}
 
-			instructions = append(instructions, solana.NewInstruction(
-				solana.MemoProgramID,
-				solana.AccountMetaSlice{
-					solana.NewAccountMeta(TestAccount.PublicKey(), false, true),
-				},
-				[]byte(fmt.Sprintf("memobench: Test %d [%s]", id, TestID)),
-			))
-
-			tx, err := solana.NewTransaction(
-				instructions,
-				recent.Value.Blockhash,
-				solana.TransactionPayer(TestAccount.PublicKey()),
-			)
-			if err != nil {
-				log.Fatalf("error creating new transaction: %v", err)
-			}
+// GenerateWallet saves a new private key to the config file, and exits so that the wallet can be funded
+func GenerateWallet() {
+	// stdin can't be read twice, and the remote config can't be updated
+	if !IsLocalConfig(ConfigFileName) {
+		log.Fatalf("private_key must be set in the config, or in the %s environment variable, when the config is read from %s", bench.PrivateKeyEnvVar, ConfigLabel())
+	}
 
-			_, err = tx.Sign(
-				func(key solana.PublicKey) *solana.PrivateKey {
-					if TestAccount.PublicKey().Equals(key) {
-						return TestAccount
-					}
-					return nil
-				},
-			)
-			if err != nil {
-				log.Fatalf("error signing new transaction: %v", err)
-			}
+	// the config is read again, the flags must not be saved to the file
+	config := ReadConfig()
 
-			// sleep until the next xx:xx:10s; then start spamming the transactions
-			startTime := time.Now().Truncate(5 * time.Second).Add(10 * time.Second)
-			sleepTime := time.Until(startTime)
+	account, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		log.Fatalf("error generating private key: %v", err)
+	}
 
-			// only log the first time, to avoid spamming logs
-			if id == 1 {
-				log.Info("Threads sleeping until starting spam", "delay", sleepTime.Truncate(time.Millisecond))
-			}
+	config.PrivateKey = account.String()
+	if err := WriteConfig(config); err != nil {
+		log.Fatalf("error saving config file: %v", err)
+	}
 
-			time.Sleep(sleepTime)
+	log.Info("test wallet generated and saved to the config file, fund it and restart", "address", account.PublicKey(), "path", ConfigFileName)
+	os.Exit(0)
+}
 
-			t0 := time.Now()
-			if err := Limiter.Wait(context.TODO()); err != nil {
-				log.Error(err.Error())
-				return
-			}
+func ParseFlags() {
+	flag.StringVar(&ConfigFileName, "config", ConfigFileName, "the path of the config file, created if missing, - to read it from stdin, or an http(s) url to fetch it from")
+	flag.StringVar(&FlagRpcUrl, "rpc-url", "", "the RPC endpoint to benchmark (overrides rpc_url)")
+	flag.Uint64Var(&FlagRateLimit, "rate-limit", 0, "the rate limit in requests per second (overrides rate_limit)")
+	flag.Uint64Var(&FlagTxCount, "tx-count", 0, "the number of transactions to send (overrides tx_count)")
+	flag.Uint64Var(&FlagCuPrice, "cu-price", 0, "the compute unit price in micro-lamports (overrides compute_unit_price_microlamports)")
+	flag.Float64Var(&FlagPrioFee, "prio-fee", 0, "deprecated, use -cu-price, the priority fee in Lamports per Compute Unit")
+	flag.StringVar(&FlagLogDir, "log-dir", "", "the directory the log and results files are saved to (overrides log_dir)")
+	flag.StringVar(&FlagTestID, "test-id", "", "a fixed test ID of 8 lowercase hex characters, instead of a random one (overrides test_id)")
+	flag.BoolVar(&FlagDryRun, "dry-run", false, "build and sign the transactions without sending them (overrides dry_run)")
+	flag.StringVar(&FlagCompare, "compare", "", "the path of a previous results file to compare the test with")
+	flag.BoolVar(&FlagQuiet, "quiet", false, "only show the results on the console, the full log is still written to the log file")
+	flag.BoolVar(&FlagNoBanner, "no-banner", false, "don't print the banner, it's also hidden when the output isn't a terminal")
+	flag.StringVar(&FlagFormat, "format", bench.SummaryFormatPlain, "the format of the results summary, plain or table")
+	flag.BoolVar(&FlagPrintConfig, "print-config", false, "print the config resolved from the config file, the flags and the environment, with the secrets redacted, and exit")
+	flag.BoolVar(&FlagVersion, "version", false, "print the version and exit")
+	flag.Parse()
+
+	// nothing else is checked, nor the config loaded
+	if FlagVersion {
+		PrintVersion()
+	}
 
-			// log if the thread had to throttle to keep under the rate limit
-			throttleTime := time.Since(t0).Truncate(time.Millisecond)
-			if throttleTime > 0 {
-				log.Info("Thread throttled to respect rate-limit, Sending now", "thread", id, "delay", throttleTime)
-			}
+	// keep track of the flags that were actually passed
+	// so that unspecified flags don't override the config file
+	flag.Visit(func(f *flag.Flag) {
+		bench.SetFlags[f.Name] = true
+	})
 
-			log.Infof("Sending Tx [%s]", tx.Signatures[0])
-
-			sig, err := sendClient.SendTransactionWithOpts(
-				context.TODO(),
-				tx,
-				rpc.TransactionOpts{
-					Encoding:      solana.EncodingBase64,
-					SkipPreflight: true,
-					MaxRetries:    &GlobalConfig.NodeRetries,
-				},
-			)
-			if err != nil {
-				if val, ok := err.(*jsonrpc.RPCError); ok {
-					log.Errorf("Error sending tx: Received RPC error: %s", val.Message)
-					return
-				}
-
-				log.Errorf("Error sending tx: %v", err)
-				return
-			}
+	if bench.SetFlags["test-id"] {
+		if err := bench.ValidateTestID(FlagTestID); err != nil {
+			log.Fatalf("invalid -test-id: %v", err)
+		}
+	}
 
-			// save the tx send time for later comparison
-			mu.Lock()
-			TxTimes[sig] = time.Now()
-			SentTransactions += 1
-			mu.Unlock()
-		}(i + 1)
+	if FlagFormat != bench.SummaryFormatPlain && FlagFormat != bench.SummaryFormatTable {
+		log.Fatalf("-format must be either %s or %s, got %q", bench.SummaryFormatPlain, bench.SummaryFormatTable, FlagFormat)
 	}
 }
 
-func DisplayBlocks() {
-	// find the first & last blocks
-	// and the block with the most transactions
-	var first uint64 = math.MaxUint64
-	var last uint64
-	var top uint64
-
-	for block, count := range TxBlocks {
-		first = uint64(math.Min(float64(first), float64(block)))
-		last = uint64(math.Max(float64(last), float64(block)))
-		top = uint64(math.Max(float64(top), float64(count)))
+func ApplyFlags(config *bench.Config) {
+	if bench.SetFlags["rpc-url"] {
+		// benchmark only the given endpoint
+		config.RpcUrl = FlagRpcUrl
+		config.Endpoints = nil
 	}
-
-	for block := first; block <= last; block++ {
-		count, ok := TxBlocks[block]
-		if !ok {
-			SimpleLogger.Printf("Block %s : %3d", message.NewPrinter(language.English).Sprintf("%d", block), count)
-			continue
-		}
-
-		// deduce the # of * characters to display
-		// use math.Ceil to round up to ensure we don't display 0 * characters
-		// (only for blocks with > 0 transactions)
-		stars := math.Ceil(float64(count) / float64(ProcessedTransactions) * 100)
-
-		SimpleLogger.Printf("Block %s : %3d | %5.1f%% | %s",
-			message.NewPrinter(language.English).Sprintf("%d", block),
-			count,
-			float64(count)/float64(ProcessedTransactions)*100,
-			strings.Repeat("*", int(stars)),
-		)
+	if bench.SetFlags["rate-limit"] {
+		config.RateLimit = FlagRateLimit
+	}
+	if bench.SetFlags["tx-count"] {
+		config.TxCount = FlagTxCount
+	}
+	if bench.SetFlags["prio-fee"] {
+		config.ComputeUnitPrice = 0
+		config.PrioFee = FlagPrioFee
+	}
+	if bench.SetFlags["cu-price"] {
+		config.ComputeUnitPrice = FlagCuPrice
+		config.PrioFee = 0
+	}
+	if bench.SetFlags["dry-run"] {
+		config.DryRun = FlagDryRun
+	}
+	if bench.SetFlags["log-dir"] {
+		config.LogDir = FlagLogDir
+	}
+	if bench.SetFlags["test-id"] {
+		config.TestID = FlagTestID
 	}
 }
 
 func main() {
-	fmt.Println("                                                                                   ")
-	fmt.Println(" ███╗   ███╗███████╗███╗   ███╗ ██████╗ ██████╗ ███████╗███╗   ██╗ ██████╗██╗  ██╗ ")
-	fmt.Println(" ████╗ ████║██╔════╝████╗ ████║██╔═══██╗██╔══██╗██╔════╝████╗  ██║██╔════╝██║  ██║ ")
-	fmt.Println(" ██╔████╔██║█████╗  ██╔████╔██║██║   ██║██████╔╝█████╗  ██╔██╗ ██║██║     ███████║ ")
-	fmt.Println(" ██║╚██╔╝██║██╔══╝  ██║╚██╔╝██║██║   ██║██╔══██╗██╔══╝  ██║╚██╗██║██║     ██╔══██║ ")
-	fmt.Println(" ██║ ╚═╝ ██║███████╗██║ ╚═╝ ██║╚██████╔╝██████╔╝███████╗██║ ╚████║╚██████╗██║  ██║ ")
-	fmt.Println(" ╚═╝     ╚═╝╚══════╝╚═╝     ╚═╝ ╚═════╝ ╚═════╝ ╚══════╝╚═╝  ╚═══╝ ╚═════╝╚═╝  ╚═╝ ")
-	fmt.Printf("%82s", Version)
-	fmt.Println()
-	fmt.Println()
-
-	c := make(chan os.Signal)
+	// the init subcommand creates the config file interactively
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		RunInit(os.Args[2:])
+		return
+	}
+
+	// parse the command line flags
+	ParseFlags()
+
+	if ShowBanner() {
+		fmt.Println("                                                                                   ")
+		fmt.Println(" ███╗   ███╗███████╗███╗   ███╗ ██████╗ ██████╗ ███████╗███╗   ██╗ ██████╗██╗  ██╗ ")
+		fmt.Println(" ████╗ ████║██╔════╝████╗ ████║██╔═══██╗██╔══██╗██╔════╝████╗  ██║██╔════╝██║  ██║ ")
+		fmt.Println(" ██╔████╔██║█████╗  ██╔████╔██║██║   ██║██████╔╝█████╗  ██╔██╗ ██║██║     ███████║ ")
+		fmt.Println(" ██║╚██╔╝██║██╔══╝  ██║╚██╔╝██║██║   ██║██╔══██╗██╔══╝  ██║╚██╗██║██║     ██╔══██║ ")
+		fmt.Println(" ██║ ╚═╝ ██║███████╗██║ ╚═╝ ██║╚██████╔╝██████╔╝███████╗██║ ╚████║╚██████╗██║  ██║ ")
+		fmt.Println(" ╚═╝     ╚═╝╚══════╝╚═╝     ╚═╝ ╚═════╝ ╚═════╝ ╚══════╝╚═╝  ╚═══╝ ╚═════╝╚═╝  ╚═╝ ")
+		fmt.Printf("%82s", Version)
+		fmt.Println()
+		fmt.Println()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
@@ -468,95 +301,56 @@ func main() {
 		log.Info("CTRL+C detected, Force stopping the test")
 		fmt.Println()
 
-		// if the websocket is not listening, exit immediately
-		// no need to call stop and log the test results
-		if !WsListener.Listening {
+		// stop the run in progress and skip the remaining endpoints,
+		// the summary is then printed and the partial results saved
+		cancel()
+
+		// nothing to save before the first run
+		if bench.CurrentBenchmark == nil {
 			os.Exit(0)
 		}
 
-		WsListener.Stop()
+		// a second interrupt gives up on the graceful stop, e.g. when the webhook is slow to respond
+		<-c
+
+		fmt.Println()
+		log.Warn("CTRL+C detected again, exiting without saving the results")
+		os.Exit(1)
 	}()
 
-	// generate the test id
-	randomBytes := make([]byte, 4)
-	_, err := rand.Read(randomBytes)
-	if err != nil {
-		panic(err)
+	// read the config file
+	config := ReadConfig()
+
+	// override the config values with the command line flags
+	ApplyFlags(config)
+
+	// load the private key from the environment if available
+	bench.ApplyEnv(config)
+
+	if FlagPrintConfig {
+		PrintConfig(config)
 	}
 
-	TestID = hex.EncodeToString(randomBytes)
+	// without a private key, generate a wallet to be funded
+	if config.PrivateKey == "" {
+		GenerateWallet()
+	}
 
-	// set up logger
-	SetupLogger()
+	// load the baseline before the test, to fail early if it's unreadable
+	if FlagCompare != "" {
+		bench.Baseline = bench.LoadBaseline(FlagCompare)
+		bench.BaselineFile = FlagCompare
+	}
 
-	// read the config file
-	GlobalConfig = ReadConfig()
-
-	// verify the private key is valid
-	VerifyPrivateKey(GlobalConfig.PrivateKey)
-
-	// set the rate limit
-	Limiter.SetLimit(rate.Limit(GlobalConfig.RateLimit))
-	Limiter.SetBurst(int(GlobalConfig.RateLimit))
-
-	SimpleLogger.Printf("Date                : %s", time.Now().UTC().Format(time.RFC1123))
-	SimpleLogger.Printf("Test Wallet         : %s", TestAccount.PublicKey().String())
-	SimpleLogger.Printf("Starting Test ID    : %s", TestID)
-	SimpleLogger.Printf("RPC URL             : %s", GlobalConfig.RpcUrl)
-	SimpleLogger.Printf("WS URL              : %s", GlobalConfig.GetWsUrl())
-	SimpleLogger.Printf("RPC Send URL        : %s", GlobalConfig.GetSendUrl())
-	SimpleLogger.Printf("Transaction Count   : %d", GlobalConfig.TxCount)
-	SimpleLogger.Printf("Rate Limit          : %d", GlobalConfig.RateLimit)
-	SimpleLogger.Printf("Priority Fee/CU     : %f Lamports (%.9f SOL)", GlobalConfig.PrioFee, (GlobalConfig.PrioFee*ComputeUnitLimit+5000)/float64(solana.LAMPORTS_PER_SOL))
-	SimpleLogger.Printf("Node Retries        : %d", GlobalConfig.NodeRetries)
-	SimpleLogger.Printf("")
-
-	// verify test wallet balance
-	AssertSufficientBalance()
-
-	// start the websocket listener
-	wg.Add(1)
-	WsListener = new(WebsocketListener)
-	go WsListener.Start()
-	wg.Wait()
-
-	SimpleLogger.Printf("")
-	SimpleLogger.Printf("Finished Test ID       : %s", TestID)
-	SimpleLogger.Printf("RPC URL                : %s", GlobalConfig.RpcUrl)
-	SimpleLogger.Printf("WS URL                 : %s", GlobalConfig.GetWsUrl())
-	SimpleLogger.Printf("RPC Send URL           : %s", GlobalConfig.GetSendUrl())
-	SimpleLogger.Printf("Transaction Count      : %d", GlobalConfig.TxCount)
-	SimpleLogger.Printf("Rate Limit             : %d", GlobalConfig.RateLimit)
-	SimpleLogger.Printf("Priority Fee/CU        : %f Lamports (%.9f SOL)", GlobalConfig.PrioFee, (GlobalConfig.PrioFee*ComputeUnitLimit+5000)/float64(solana.LAMPORTS_PER_SOL))
-	SimpleLogger.Printf("Node Retries           : %d", GlobalConfig.NodeRetries)
-	SimpleLogger.Printf("Transactions Landed    : %d/%d (%.1f%%)", ProcessedTransactions, SentTransactions, float64(ProcessedTransactions)/float64(SentTransactions)*100.0)
-
-	// calculate landing time results, if there was any
-	if len(TxDeltas) > 0 {
-		var landingTimes []float64
-		for _, v := range TxDeltas {
-			landingTimes = append(landingTimes, float64(v.Nanoseconds()))
-		}
+	bench.Quiet = FlagQuiet
+	bench.SummaryFormat = FlagFormat
+	bench.ConfigSource = ConfigLabel()
 
-		minDelta, _ := stats.Min(landingTimes)
-		maxDelta, _ := stats.Max(landingTimes)
-		avg, _ := stats.Mean(landingTimes)
-		median, _ := stats.Median(landingTimes)
-		p90, _ := stats.Percentile(landingTimes, 90)
-		p95, _ := stats.Percentile(landingTimes, 95)
-		p99, _ := stats.Percentile(landingTimes, 99)
-
-		SimpleLogger.Printf("Min Tx Landing Time    : %s", (time.Duration(minDelta)).Truncate(time.Millisecond))
-		SimpleLogger.Printf("Max Tx Landing Time    : %s", (time.Duration(maxDelta)).Truncate(time.Millisecond))
-		SimpleLogger.Printf("Avg Tx Landing Time    : %s", (time.Duration(avg)).Truncate(time.Millisecond))
-		SimpleLogger.Printf("Median Tx Landing Time : %s", (time.Duration(median)).Truncate(time.Millisecond))
-		SimpleLogger.Printf("P90 Tx Landing Time    : %s", (time.Duration(p90)).Truncate(time.Millisecond))
-		SimpleLogger.Printf("P95 Tx Landing Time    : %s", (time.Duration(p95)).Truncate(time.Millisecond))
-		SimpleLogger.Printf("P99 Tx Landing Time    : %s", (time.Duration(p99)).Truncate(time.Millisecond))
-		SimpleLogger.Printf("")
-
-		DisplayBlocks()
-	}
-	fmt.Println()
-	fmt.Printf("Benchmark results saved to %s\n", LogFileName)
+	if _, err := bench.Run(ctx, config); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	if !bench.MeetsMinLandingRate(bench.Benchmarks) {
+		os.Exit(1)
+	}
 }